@@ -0,0 +1,95 @@
+package twigsnake
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// asyncWrite is a single write waiting in the asynchronous queue.
+type asyncWrite struct {
+	w io.Writer
+	p []byte
+}
+
+// runAsync performs queued writes until the queue is closed, then closes done.
+func (l *Logger) runAsync(queue <-chan asyncWrite, done chan<- struct{}) {
+	defer close(done)
+	for item := range queue {
+		if _, err := item.w.Write(item.p); err != nil {
+			l.writeFailed(err)
+		}
+		atomic.AddInt64(&l.pending, -1)
+	}
+}
+
+// SetAsync switches logger to asynchronous mode, where messages are formatted by the caller but written to their
+// destinations by a background goroutine, so slow writers don't hold up logging code. Up to bufferSize writes may wait
+// in the queue; when it is full, logging blocks until there is room, unless OverflowDrop is set with SetAsyncOverflow.
+// Zero bufferSize switches back to synchronous mode after all queued writes are done. Changing the buffer size also
+// waits for the queue to drain. Use Flush to wait for queued writes and Close to stop the background goroutine.
+func (l *Logger) SetAsync(bufferSize int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.async != nil {
+		close(r.async)
+		<-r.asyncDone
+		r.async = nil
+	}
+	if bufferSize > 0 {
+		r.async = make(chan asyncWrite, bufferSize)
+		r.asyncDone = make(chan struct{})
+		go r.runAsync(r.async, r.asyncDone)
+	}
+}
+
+// PendingCount returns the number of writes queued but not yet completed in asynchronous mode, plus, for loggers
+// created with NewBuffered, writes waiting in the buffer. It is always zero for synchronous unbuffered loggers.
+// Counters are read atomically, so it is safe to poll it from any goroutine, e.g. for monitoring.
+func (l *Logger) PendingCount() int {
+	r := l.base()
+	n := atomic.LoadInt64(&r.pending)
+	if r.buffer != nil {
+		n += atomic.LoadInt64(&r.buffer.pending)
+	}
+	return int(n)
+}
+
+// Overflow is the policy of handling writes which don't fit in the full asynchronous queue.
+type Overflow int
+
+// Overflow policies:
+const (
+	OverflowBlock Overflow = iota // wait until there is room in the queue, so nothing is lost; the default
+	OverflowDrop                  // drop the write and count it, so logging never waits for slow writers
+)
+
+// SetAsyncOverflow sets policy of handling writes when the asynchronous queue is full (see SetAsync). Writes dropped
+// with OverflowDrop are counted by Dropped.
+func (l *Logger) SetAsyncOverflow(policy Overflow) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.asyncDrop = policy == OverflowDrop
+}
+
+// Dropped returns the number of writes dropped so far because the asynchronous queue was full (see SetAsyncOverflow).
+// It is safe to poll it from any goroutine, e.g. for monitoring.
+func (l *Logger) Dropped() int64 {
+	return atomic.LoadInt64(&l.base().dropped)
+}
+
+// NewAsync creates new Logger instance with specified logging level and output, working in asynchronous mode with queue
+// of bufferSize writes (see SetAsync). Options are applied as by New. Close the logger before exit to make sure all
+// queued messages are written.
+func NewAsync(lvl int, dest io.Writer, bufferSize int, opts ...Option) (*Logger, error) {
+	return New(lvl, dest, append(opts[:len(opts):len(opts)], WithAsync(bufferSize))...)
+}
+
+// WithAsync switches logger to asynchronous mode with queue of bufferSize writes (see SetAsync).
+func WithAsync(bufferSize int) Option {
+	return func(l *Logger) {
+		l.SetAsync(bufferSize)
+	}
+}
@@ -0,0 +1,158 @@
+package twigsnake
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter is a writer blocking until its gate is opened, standing for a slow destination.
+type gatedWriter struct {
+	gate chan struct{}
+	mu   sync.Mutex
+	buf  bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return lines(&w.buf)
+}
+
+func TestAsync(t *testing.T) {
+	const messages = 100
+	w := newGatedWriter()
+	l, _ := newTestLogger(t, LOG_DEBUG, WithAsync(messages))
+	l.SetOutput(w)
+
+	for i := 0; i < messages; i++ {
+		l.Infof("m%d", i)
+	}
+	// Logging doesn't wait for the blocked writer.
+	if got := l.PendingCount(); got != messages {
+		t.Errorf("PendingCount() = %d, want %d", got, messages)
+	}
+	if got := w.lines(); len(got) != 0 {
+		t.Errorf("blocked writer received %q", got)
+	}
+
+	close(w.gate)
+	if err := l.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := l.PendingCount(); got != 0 {
+		t.Errorf("PendingCount() = %d after Flush, want 0", got)
+	}
+	got := w.lines()
+	if len(got) != messages {
+		t.Fatalf("%d lines written after Flush, want %d", len(got), messages)
+	}
+	for i, line := range got {
+		if want := fmt.Sprintf("[INFO] m%d", i); line != want {
+			t.Fatalf("line %d is %q, want %q", i, line, want)
+		}
+	}
+
+	// Logging keeps working after Flush.
+	l.Info("after flush")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := w.lines(); got[len(got)-1] != "[INFO] after flush" {
+		t.Errorf("last line %q, want message logged after Flush", got[len(got)-1])
+	}
+}
+
+func TestAsyncOverflow(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      Overflow
+		wantDropped int64
+	}{
+		{"drop", OverflowDrop, 3},
+		{"block", OverflowBlock, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newGatedWriter()
+			l, _ := newTestLogger(t, LOG_DEBUG, WithAsync(2))
+			l.SetOutput(w)
+			l.SetAsyncOverflow(tt.policy)
+
+			// The first message is taken by the background writer, which blocks, the next two fill the queue and the
+			// rest overflow it.
+			l.Info("m0")
+			for l.PendingCount() != 1 || len(l.async) != 0 {
+				runtime.Gosched()
+			}
+			logged := make(chan struct{})
+			go func() {
+				defer close(logged)
+				for i := 1; i < 6; i++ {
+					l.Infof("m%d", i)
+				}
+			}()
+			if tt.policy == OverflowDrop {
+				// Dropping never waits for the writer.
+				<-logged
+			} else {
+				select {
+				case <-logged:
+					t.Error("logging didn't wait for room in the full queue")
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+			close(w.gate)
+			<-logged
+			l.Close()
+
+			if got := l.Dropped(); got != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", got, tt.wantDropped)
+			}
+			if got, want := len(w.lines()), 6-int(tt.wantDropped); got != want {
+				t.Errorf("%d lines written, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestAsyncClose(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewAsync(LOG_DEBUG, &buf, 10, WithFlags(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("m")
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	l.mu.Lock()
+	done, stopped := l.asyncDone, l.async == nil
+	l.mu.Unlock()
+	if !stopped {
+		t.Error("asynchronous queue still in use after Close")
+	}
+	select {
+	case <-done:
+	default:
+		t.Error("background writer still running after Close")
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[INFO] m" {
+		t.Errorf("output %q, want %q", got, "[INFO] m")
+	}
+}
@@ -0,0 +1,79 @@
+package twigsnake
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// Fixed prefix, level name and output flags of audit messages: UTC timestamps with microseconds.
+const (
+	auditPrefix    = "[AUDIT] "
+	auditLevelName = "audit"
+	auditFlags     = log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC
+)
+
+// SetAuditOutput sets destination of audit messages printed with Audit, e.g. append-only file or write-once storage kept
+// apart from operational logs. Nil writer, the default, makes audit messages go to the main output instead.
+func (l *Logger) SetAuditOutput(w io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auditOutput = w
+}
+
+// SetAuditMirror enables or disables copying of audit messages to the main output when audit output is set with
+// SetAuditOutput, so that operational logs show audit events in context.
+func (l *Logger) SetAuditMirror(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auditMirror = enabled
+}
+
+// Audit prints security or compliance event to the audit output (see SetAuditOutput). Audit messages are not subject
+// to severity levels: they are printed regardless of logging level, token buckets and output levels, and don't count
+// towards Counts. In the audit output they are always rendered as text with "[AUDIT] " prefix and UTC timestamp with
+// microseconds, whatever the logger's format, prefixes and flags are; copies in the main output are rendered with the
+// logger's format, with "audit" level name in structured formats and "[AUDIT] " prefix in text ones (see Entry.Audit).
+// Handles arguments in the same manner as log.Print.
+func (l *Logger) Audit(v ...interface{}) {
+	r := l.base()
+	v, fields := l.trailingFields(v)
+	msg, fields := trimEOL(fmt.Sprint(v...)), resolveLazy(l.withName(fields))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := Entry{
+		Time:          r.now(),
+		Level:         LOG_EMERG,
+		Message:       msg,
+		Fields:        fields,
+		Prefix:        auditPrefix,
+		Flags:         auditFlags,
+		MaxDepth:      r.maxDepth,
+		BytesEncoding: r.bytesEnc,
+		Facility:      r.facility,
+		Audit:         true,
+	}
+	if r.deterministic {
+		e.Time = deterministicTime
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if r.auditOutput != nil {
+		*buf = append(textFormatter{}.Format(*buf, &e), r.lineEnding...)
+		r.writeRaw(r.auditOutput, *buf)
+		if !r.auditMirror {
+			return
+		}
+	}
+
+	e.TimeKey, e.LevelKey, e.MessageKey = r.timeKey, r.levelKey, r.messageKey
+	if len(r.sinks) > 0 {
+		r.emitSinks(&e, r.sinks)
+	} else if w := r.writerFor(&e); w != nil {
+		*buf = r.formatLine((*buf)[:0], r.formatter, &e)
+		r.writeRaw(w, *buf)
+	}
+}
@@ -0,0 +1,75 @@
+package twigsnake
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAuditLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *Logger)
+		want  func(line string) bool
+	}{
+		{"text", func(l *Logger) {}, func(line string) bool {
+			return line == "[AUDIT] 2021/03/05 14:30:15.123456 login user=ann"
+		}},
+		{"JSON", func(l *Logger) { l.SetFormat(FormatJSON) }, func(line string) bool {
+			var v struct{ Level, Msg, User string }
+			return json.Unmarshal([]byte(line), &v) == nil && v.Level == "audit" && v.Msg == "login" && v.User == "ann"
+		}},
+		{"logfmt", func(l *Logger) { l.SetFormat(FormatLogfmt) }, func(line string) bool {
+			return strings.HasSuffix(line, " level=audit msg=login user=ann")
+		}},
+		{"CSV", func(l *Logger) { l.SetFormat(FormatCSV) }, func(line string) bool {
+			return strings.Contains(line, ",audit,login,")
+		}},
+		{"numeric prefix", func(l *Logger) { l.SetNumericPrefix(true) }, func(line string) bool {
+			return line == "<109>[AUDIT] 2021/03/05 14:30:15.123456 login user=ann"
+		}},
+		{"RFC 5424", func(l *Logger) { l.SetFormatter(NewRFC5424Formatter("host", "app")) }, func(line string) bool {
+			return strings.HasPrefix(line, "<109>1 ")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_ERROR)
+			tt.setup(l)
+			l.With("user", "ann").Audit("login")
+			got := lines(buf)
+			if len(got) == 0 || !tt.want(got[len(got)-1]) {
+				t.Errorf("output %q doesn't report audit level", got)
+			}
+		})
+	}
+}
+
+func TestAuditOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		mirror bool
+	}{
+		{"separate", false},
+		{"mirrored", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_EMERG, WithFormat(FormatJSON))
+			var audit bytes.Buffer
+			l.SetAuditOutput(&audit)
+			l.SetAuditMirror(tt.mirror)
+			l.Audit("login")
+			if got, want := audit.String(), "[AUDIT] 2021/03/05 14:30:15.123456 login\n"; got != want {
+				t.Errorf("audit output %q, want %q", got, want)
+			}
+			if got := strings.Contains(buf.String(), `"level":"audit"`); got != tt.mirror {
+				t.Errorf("main output %q, mirrored = %v, want %v", buf.String(), got, tt.mirror)
+			}
+			if n := l.Counts()[LOG_EMERG]; n != 0 {
+				t.Errorf("audit messages counted as emergencies: %d", n)
+			}
+		})
+	}
+}
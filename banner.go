@@ -0,0 +1,121 @@
+package twigsnake
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// bannerWidth is the minimal width of banner borders.
+const bannerWidth = 40
+
+// Banner prints a block of lines on given level, typically at service startup: fields are listed as aligned "key : value"
+// lines sorted by key, framed with borders made of '=' characters. Lines are written as a batch (see beginBatch), a single
+// write per destination, so messages logged concurrently can't get in between. Every line carries the context of l,
+// such as its name and fields added with With, and the call site if caller reporting is enabled. The block as a whole
+// counts as a single message for deduplication and rate limiting: it is either printed completely or dropped. Nothing
+// is printed if the level is disabled or invalid (see SetInvalidLevelPolicy).
+func (l *Logger) Banner(level int, fields map[string]string) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	keys := make([]string, 0, len(fields))
+	keyWidth := 0
+	for k := range fields {
+		keys = append(keys, k)
+		if len(k) > keyWidth {
+			keyWidth = len(k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys)+2)
+	width := bannerWidth
+	for _, k := range keys {
+		line := k + strings.Repeat(" ", keyWidth-len(k)) + " : " + fields[k]
+		if len(line) > width {
+			width = len(line)
+		}
+		lines = append(lines, line)
+	}
+	border := strings.Repeat("=", width)
+
+	r := l.base()
+	lg := r.loggers()[level]
+	ctx := l.withName(nil)
+	pc := callerPC(1)
+
+	block := append(append([]string{border}, lines...), border)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entry(lg, level, strings.Join(block, "\n"), ctx)
+	r.addCaller(&e, pc, 0)
+	r.beginBatch()
+	defer r.endBatch()
+	if !r.admit(&e, e.Time) {
+		return
+	}
+	for _, s := range block {
+		e.Message = s
+		r.emit(&e)
+	}
+}
+
+// Default style of section markers printed by Section.
+const (
+	defaultSectionFill  = '='
+	defaultSectionWidth = 40
+)
+
+// minSectionFill is the minimal number of fill characters on each side of section title.
+const minSectionFill = 3
+
+// SetSectionStyle sets fill character and total width of section markers printed by Section. Zero fill or non-positive
+// width restore the defaults: '=' and 40 characters.
+func (l *Logger) SetSectionStyle(fill rune, width int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sectionFill = fill
+	r.sectionWidth = width
+}
+
+// Section prints section marker with given title centered between fill characters, e.g. "======== title ========", to
+// visually separate phases of a long run. Markers without title consist of fill characters only. Nothing is printed if
+// the level is disabled or invalid.
+func (l *Logger) Section(level int, title string) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	fill, width := r.sectionFill, r.sectionWidth
+	r.mu.Unlock()
+	if fill == 0 {
+		fill = defaultSectionFill
+	}
+	if width <= 0 {
+		width = defaultSectionWidth
+	}
+
+	f := string(fill)
+	if title == "" {
+		l.output(level, strings.Repeat(f, width), nil)
+		return
+	}
+	pad := width - utf8.RuneCountInString(title) - 2
+	left := pad / 2
+	if left < minSectionFill {
+		left = minSectionFill
+	}
+	right := pad - left
+	if right < minSectionFill {
+		right = minSectionFill
+	}
+	l.output(level, strings.Repeat(f, left)+" "+title+" "+strings.Repeat(f, right), nil)
+}
@@ -0,0 +1,72 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestBanner(t *testing.T) {
+	var buf bytes.Buffer
+	writes := 0
+	w := funcWriter(func(p []byte) (int, error) {
+		writes++
+		return buf.Write(p)
+	})
+	l, err := New(LOG_DEBUG, w, WithFlags(log.Lshortfile|log.Lmsgprefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Named("app").With("k", "v").Banner(LOG_INFO, map[string]string{"version": "1.2.3", "commit": "abc"})
+
+	if writes != 1 {
+		t.Errorf("banner written in %d writes, want 1", writes)
+	}
+	border := strings.Repeat("=", bannerWidth)
+	want := []string{border, "commit  : abc", "version : 1.2.3", border}
+	got := lines(&buf)
+	if len(got) != len(want) {
+		t.Fatalf("output:\n%s\nwant %d lines", buf.String(), len(want))
+	}
+	for i, line := range got {
+		if !strings.HasPrefix(line, "banner_test.go:") {
+			t.Errorf("line %q doesn't report call site in banner_test.go", line)
+		}
+		if !strings.HasSuffix(line, " [INFO] "+want[i]+" logger=app k=v") {
+			t.Errorf("line %q, want %q with context fields", line, want[i])
+		}
+	}
+}
+
+func TestBannerDisabledLevel(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_NOTICE)
+	l.Banner(LOG_INFO, map[string]string{"a": "b"})
+	if buf.Len() != 0 {
+		t.Errorf("printed %q on disabled level", buf.String())
+	}
+}
+
+func TestSection(t *testing.T) {
+	tests := []struct {
+		name  string
+		fill  rune
+		width int
+		title string
+		want  string
+	}{
+		{"default style", 0, 0, "setup", strings.Repeat("=", 16) + " setup " + strings.Repeat("=", 17)},
+		{"no title", '-', 10, "", "----------"},
+		{"long title", '*', 10, "a long title", "*** a long title ***"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.With().SetSectionStyle(tt.fill, tt.width)
+			l.Section(LOG_INFO, tt.title)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != "[INFO] "+tt.want {
+				t.Errorf("output %q, want %q", got, "[INFO] "+tt.want)
+			}
+		})
+	}
+}
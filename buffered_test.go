@@ -0,0 +1,93 @@
+package twigsnake
+
+import (
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closingWriter is an open gatedWriter counting Close calls.
+type closingWriter struct {
+	*gatedWriter
+	closed int32
+}
+
+func newClosingWriter() *closingWriter {
+	w := &closingWriter{gatedWriter: newGatedWriter()}
+	close(w.gate)
+	return w
+}
+
+func (w *closingWriter) Close() error {
+	atomic.AddInt32(&w.closed, 1)
+	return nil
+}
+
+func newTestBuffered(t *testing.T, w *closingWriter, flushInterval time.Duration) *Logger {
+	t.Helper()
+	l, err := NewBuffered(LOG_DEBUG, w, flushInterval)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetFlags(log.Lmsgprefix)
+	return l
+}
+
+func TestBuffered(t *testing.T) {
+	w := newClosingWriter()
+	l := newTestBuffered(t, w, time.Hour)
+
+	l.Info("a")
+	l.Warn("b")
+	if got := w.lines(); len(got) != 0 {
+		t.Fatalf("written before flush: %q", got)
+	}
+	if got := l.PendingCount(); got != 2 {
+		t.Errorf("PendingCount() = %d, want 2", got)
+	}
+
+	// Alert messages are written right away, after the buffered ones.
+	l.Alert("c")
+	want := []string{"[INFO] a", "[WARN] b", "[ALERT] c"}
+	if got := w.lines(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("after alert:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := l.PendingCount(); got != 0 {
+		t.Errorf("PendingCount() = %d after alert, want 0", got)
+	}
+
+	l.Emerg("d")
+	l.Debug("e")
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	l.Notice("f")
+	if got := l.PendingCount(); got != 1 {
+		t.Errorf("PendingCount() = %d, want 1", got)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, "[EMERG] d", "[DEBUG] e", "[NOTICE] f")
+	if got := w.lines(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("after close:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := atomic.LoadInt32(&w.closed); got != 1 {
+		t.Errorf("writer closed %d times, want 1", got)
+	}
+}
+
+func TestBufferedPeriodicFlush(t *testing.T) {
+	w := newClosingWriter()
+	l := newTestBuffered(t, w, time.Millisecond)
+	defer l.Close()
+
+	l.Info("a")
+	l.Info("b")
+	waitFor(t, "periodic flush", func() bool { return len(w.lines()) == 2 })
+	if got := l.PendingCount(); got != 0 {
+		t.Errorf("PendingCount() = %d after flush, want 0", got)
+	}
+}
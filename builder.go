@@ -0,0 +1,101 @@
+package twigsnake
+
+import "fmt"
+
+// builderInline is the number of fields Builder keeps in place, without allocating.
+const builderInline = 4
+
+// Builder accumulates structured fields of a single message, see Logger.Field. It is meant to be used in a single chain
+// of calls: builders extended by Field share storage of fields beyond the first few, so a partially built one must not
+// be extended twice.
+type Builder struct {
+	l      *Logger
+	n      int                  // number of fields in inline
+	inline [builderInline]Field // the first fields, kept in place
+	more   []Field              // fields beyond the inline ones
+}
+
+// Field starts building a message with structured fields: logger.Field("user", u).Field("id", id).Info("login").
+// Fields are only rendered if the level of the final call is enabled. Up to four fields are collected without
+// allocating, so when the level is disabled the chain itself costs nothing but copying. Values are converted to
+// interface{} before the level is known, though, which allocates for non-constant values other than small integers,
+// e.g. for a string variable; where that matters, or more fields are too costly to collect, check IsLevelEnabled first.
+func (l *Logger) Field(key string, value interface{}) Builder {
+	b := Builder{l: l, n: 1}
+	b.inline[0] = Field{key, value}
+	return b
+}
+
+// Field adds another field to the message.
+func (b Builder) Field(key string, value interface{}) Builder {
+	if b.n < len(b.inline) {
+		b.inline[b.n] = Field{key, value}
+		b.n++
+	} else {
+		b.more = append(b.more, Field{key, value})
+	}
+	return b
+}
+
+// fields returns accumulated fields in a newly allocated slice, so that b itself can stay on the stack.
+func (b *Builder) fields() []Field {
+	fields := make([]Field, 0, b.n+len(b.more))
+	fields = append(fields, b.inline[:b.n]...)
+	return append(fields, b.more...)
+}
+
+// Emerg prints emergency message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Emerg(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_EMERG) {
+		b.l.output(LOG_EMERG, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Alert prints alert message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Alert(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_ALERT) {
+		b.l.output(LOG_ALERT, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Crit prints critical message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Crit(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_CRIT) {
+		b.l.output(LOG_CRIT, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Error prints error message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Error(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_ERROR) {
+		b.l.output(LOG_ERROR, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Warn prints warning message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Warn(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_WARN) {
+		b.l.output(LOG_WARN, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Notice prints notification message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Notice(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_NOTICE) {
+		b.l.output(LOG_NOTICE, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Info prints informational message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Info(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_INFO) {
+		b.l.output(LOG_INFO, fmt.Sprint(v...), b.fields())
+	}
+}
+
+// Debug prints debugging message with accumulated fields. Arguments are handled in the same manner as log.Print.
+func (b Builder) Debug(v ...interface{}) {
+	if b.l.EffectiveEnabled(LOG_DEBUG) {
+		b.l.output(LOG_DEBUG, fmt.Sprint(v...), b.fields())
+	}
+}
@@ -0,0 +1,70 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+		want string
+	}{
+		{"single field", func(l *Logger) { l.Field("a", 1).Info("m") }, "[INFO] m a=1"},
+		{"order kept", func(l *Logger) { l.Field("b", 2).Field("a", 1).Warn("m") }, "[WARN] m b=2 a=1"},
+		{"beyond inline fields", func(l *Logger) {
+			l.Field("a", 1).Field("b", 2).Field("c", 3).Field("d", 4).Field("e", 5).Field("f", 6).Error("m")
+		}, "[ERROR] m a=1 b=2 c=3 d=4 e=5 f=6"},
+		{"With context first", func(l *Logger) { l.With("w", 0).Field("a", 1).Notice("m") }, "[NOTICE] m w=0 a=1"},
+		{"disabled level", func(l *Logger) { l.Field("a", 1).Debug("m") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			tt.log(l)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderBranches(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	base := l.Field("a", 1)
+	base.Field("b", 2).Info("first")
+	base.Field("c", 3).Info("second")
+	want := []string{"[INFO] first a=1 b=2", "[INFO] second a=1 c=3"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+// boxed keeps values converted to interface{} by tests measuring the cost of the conversion alone.
+var boxed [2]interface{}
+
+func TestBuilderDisabledAllocs(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_NOTICE)
+	if allocs := testing.AllocsPerRun(100, func() {
+		l.Field("user", "ann").Field("id", 7).Field("admin", true).Info("login")
+	}); allocs != 0 {
+		t.Errorf("disabled builder chain with constant values made %v allocations, want 0", allocs)
+	}
+
+	// Non-constant strings and integers above 255 are converted to interface{} before the level is checked, which
+	// allocates; the chain itself must not add to that.
+	users := []string{"ann", "bob"}
+	i := 0
+	boxing := testing.AllocsPerRun(100, func() {
+		i++
+		boxed[0], boxed[1] = users[i%2], 1000+i
+	})
+	if allocs := testing.AllocsPerRun(100, func() {
+		i++
+		l.Field("user", users[i%2]).Field("id", 1000+i).Field("admin", true).Info("login")
+	}); allocs != boxing {
+		t.Errorf("disabled builder chain with variable values made %v allocations, want %v made by boxing them",
+			allocs, boxing)
+	}
+}
@@ -0,0 +1,103 @@
+package twigsnake
+
+import (
+	"runtime"
+	"strings"
+)
+
+// SetCallerFunc enables or disables reporting of the calling function. When enabled, every message gets caller=pkg.Func
+// field appended, where pkg is the last element of the caller's package path. Walking the stack is relatively expensive,
+// so this option is disabled by default and costs nothing until turned on.
+func (l *Logger) SetCallerFunc(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callerFunc = enabled
+}
+
+// SetCaller enables or disables reporting of the call site as file:line regardless of log.Lshortfile and log.Llongfile
+// flags of underlying loggers: levels without either flag behave as if log.Lshortfile was set. The reported site is the
+// caller of the printing method (Info, Infof, Infoln, Infow and so on), not the package's own source. Text formats print
+// it before the message, structured formats such as FormatJSON put it in "source" field.
+func (l *Logger) SetCaller(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callerSite = enabled
+}
+
+// SetCallerPackage enables or disables reporting of the calling function's package. When enabled, every message gets
+// pkg=name field appended, where name is the last element of the caller's package path, e.g. "http" for net/http. It is
+// often enough to tell where a message came from and is cleaner than full file paths. The option can be used alone or
+// together with SetCallerFunc, in which case the stack is walked only once.
+func (l *Logger) SetCallerPackage(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callerPkg = enabled
+}
+
+// addCaller fills in call site of entry e if any of SetCallerFunc, SetCallerPackage or flags of e ask for it: appends
+// caller and pkg fields and sets File and Line. The call site is given by program counter pc as returned by
+// runtime.Callers; zero pc stands for the function skip frames above caller of addCaller. Backing array of e.Fields is
+// never modified. Must be called with l.mu held.
+func (l *Logger) addCaller(e *Entry, pc uintptr, skip int) {
+	needFile := callerNeeded(e.Flags)
+	if !l.callerFunc && !l.callerPkg && !needFile {
+		return
+	}
+	if pc == 0 {
+		pc = callerPC(skip + 1)
+	}
+	var frame runtime.Frame
+	if pc != 0 {
+		frame, _ = runtime.CallersFrames([]uintptr{pc}).Next()
+	}
+
+	if l.callerFunc || l.callerPkg {
+		fields := e.Fields[:len(e.Fields):len(e.Fields)]
+		if l.callerFunc {
+			fields = append(fields, Field{"caller", shortFuncName(frame.Function)})
+		}
+		if l.callerPkg {
+			fields = append(fields, Field{"pkg", packageName(frame.Function)})
+		}
+		e.Fields = fields
+	}
+	if needFile {
+		e.File, e.Line = frame.File, frame.Line
+		if e.File == "" {
+			e.File = "???"
+		}
+	}
+}
+
+// callerPC returns program counter of the function skip frames above its caller (skip 0 means caller of callerPC
+// itself) in the form runtime.Callers returns it, or zero if the stack is not deep enough.
+func callerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// shortFuncName strips import path from fully qualified function name, e.g. "github.com/user/pkg.(*T).Method" becomes
+// "pkg.(*T).Method".
+func shortFuncName(name string) string {
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// packageName returns the last element of package path of fully qualified function name, e.g.
+// "github.com/user/pkg.(*T).Method" becomes "pkg". Dots in the last path element, which runtime escapes as "%2e", are
+// restored, so "gopkg.in/yaml%2ev3.Marshal" becomes "yaml.v3".
+func packageName(name string) string {
+	name = shortFuncName(name)
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return strings.Replace(name, "%2e", ".", -1)
+}
@@ -0,0 +1,218 @@
+package twigsnake
+
+import (
+	"encoding/json"
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{"Info", func(l *Logger) { l.Info("m") }},
+		{"Infof", func(l *Logger) { l.Infof("%s", "m") }},
+		{"Infoln", func(l *Logger) { l.Infoln("m") }},
+		{"Infow", func(l *Logger) { l.Infow("m", "k", "v") }},
+		{"Errorf", func(l *Logger) { l.Errorf("%s", "m") }},
+		{"Debugln", func(l *Logger) { l.Debugln("m") }},
+		{"With logger", func(l *Logger) { l.With("k", "v").Warn("m") }},
+		{"named logger", func(l *Logger) { l.Named("db").Notice("m") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.With().SetCaller(true)
+			tt.log(l)
+			if got := buf.String(); !strings.HasPrefix(got, "caller_test.go:") {
+				t.Errorf("output %q doesn't start with call site in caller_test.go", got)
+			}
+		})
+	}
+}
+
+func TestSetCallerFunc(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+	}{
+		{"Info", func(l *Logger) { l.Info("m") }},
+		{"Infof", func(l *Logger) { l.Infof("%s", "m") }},
+		{"Infow", func(l *Logger) { l.Infow("m", "k", "v") }},
+		{"With logger", func(l *Logger) { l.With("k", "v").Info("m") }},
+		{"LogAt", func(l *Logger) { l.LogAt(testTime, LOG_INFO, "m") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatJSON))
+			l.Named("x").SetCallerFunc(true)
+			tt.log(l)
+			var got struct{ Caller string }
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if want := "twigsnake.TestSetCallerFunc.func"; !strings.HasPrefix(got.Caller, want) {
+				t.Errorf("caller = %q, want prefix %q", got.Caller, want)
+			}
+		})
+	}
+}
+
+func callerFuncOfHelper(l *Logger) { l.Info("m") }
+
+func TestSetCallerFuncNamedFunction(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	l.SetCallerFunc(true)
+	callerFuncOfHelper(l)
+	if got, want := strings.TrimSpace(buf.String()), "[INFO] m caller=twigsnake.callerFuncOfHelper"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestCallerSiteAndFunc(t *testing.T) {
+	tests := []struct {
+		format         Format
+		source, caller string // keys of call site and calling function as rendered in the format
+	}{
+		{FormatJSON, `"source":"`, `"caller":`},
+		{FormatLogfmt, " source=", " caller="},
+		{FormatRFC5424, ` source="`, ` caller="`},
+	}
+	for _, tt := range tests {
+		l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(tt.format), WithCaller(true))
+		l.SetCallerFunc(true)
+		callerFuncOfHelper(l)
+		got := buf.String()
+		if i := strings.Index(got, tt.source); i < 0 || !strings.Contains(got[i:], "caller_test.go:") {
+			t.Errorf("format %d: output %q lacks call site in caller_test.go", tt.format, got)
+		}
+		if n := strings.Count(got, tt.caller); n != 1 {
+			t.Errorf("format %d: output %q has %d caller keys, want 1", tt.format, got, n)
+		}
+		if !strings.Contains(got, "twigsnake.callerFuncOfHelper") {
+			t.Errorf("format %d: output %q lacks calling function", tt.format, got)
+		}
+	}
+}
+
+func TestWithCaller(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithCaller(true))
+	l.Info("m")
+	if got := buf.String(); !strings.HasPrefix(got, "caller_test.go:") || !strings.HasSuffix(got, " [INFO] m\n") {
+		t.Errorf("output %q, want call site in caller_test.go followed by the message", got)
+	}
+
+	l, buf = newTestLogger(t, LOG_DEBUG, WithCaller(true), WithCaller(false))
+	l.Info("m")
+	if got, want := buf.String(), "[INFO] m\n"; got != want {
+		t.Errorf("output after WithCaller(false) %q, want %q", got, want)
+	}
+}
+
+func TestSetCallerPackage(t *testing.T) {
+	tests := []struct {
+		name       string
+		callerFunc bool
+		want       string
+	}{
+		{"alone", false, "[INFO] m pkg=twigsnake"},
+		{"with caller function", true, "[INFO] m caller=twigsnake.callerFuncOfHelper pkg=twigsnake"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.With().SetCallerPackage(true)
+			l.SetCallerFunc(tt.callerFunc)
+			callerFuncOfHelper(l)
+			if got := strings.TrimSpace(buf.String()); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	tests := []struct {
+		fn, want string
+	}{
+		{"main.main", "main"},
+		{"net/http.(*Server).Serve", "http"},
+		{"github.com/user/pkg.(*T).Method.func1", "pkg"},
+		{"gopkg.in/yaml%2ev3.Marshal", "yaml.v3"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := packageName(tt.fn); got != tt.want {
+			t.Errorf("packageName(%q) = %q, want %q", tt.fn, got, tt.want)
+		}
+	}
+}
+
+func TestShortfileReportsCaller(t *testing.T) {
+	type method struct {
+		name string
+		log  func(l *Logger)
+	}
+	var tests []method
+	levels := []struct {
+		name    string
+		print   func(l *Logger) func(v ...interface{})
+		printf  func(l *Logger) func(format string, v ...interface{})
+		println func(l *Logger) func(v ...interface{})
+	}{
+		{"Emerg", func(l *Logger) func(...interface{}) { return l.Emerg },
+			func(l *Logger) func(string, ...interface{}) { return l.Emergf },
+			func(l *Logger) func(...interface{}) { return l.Emergln }},
+		{"Alert", func(l *Logger) func(...interface{}) { return l.Alert },
+			func(l *Logger) func(string, ...interface{}) { return l.Alertf },
+			func(l *Logger) func(...interface{}) { return l.Alertln }},
+		{"Crit", func(l *Logger) func(...interface{}) { return l.Crit },
+			func(l *Logger) func(string, ...interface{}) { return l.Critf },
+			func(l *Logger) func(...interface{}) { return l.Critln }},
+		{"Error", func(l *Logger) func(...interface{}) { return l.Error },
+			func(l *Logger) func(string, ...interface{}) { return l.Errorf },
+			func(l *Logger) func(...interface{}) { return l.Errorln }},
+		{"Warn", func(l *Logger) func(...interface{}) { return l.Warn },
+			func(l *Logger) func(string, ...interface{}) { return l.Warnf },
+			func(l *Logger) func(...interface{}) { return l.Warnln }},
+		{"Notice", func(l *Logger) func(...interface{}) { return l.Notice },
+			func(l *Logger) func(string, ...interface{}) { return l.Noticef },
+			func(l *Logger) func(...interface{}) { return l.Noticeln }},
+		{"Info", func(l *Logger) func(...interface{}) { return l.Info },
+			func(l *Logger) func(string, ...interface{}) { return l.Infof },
+			func(l *Logger) func(...interface{}) { return l.Infoln }},
+		{"Debug", func(l *Logger) func(...interface{}) { return l.Debug },
+			func(l *Logger) func(string, ...interface{}) { return l.Debugf },
+			func(l *Logger) func(...interface{}) { return l.Debugln }},
+	}
+	for _, lv := range levels {
+		lv := lv
+		tests = append(tests,
+			method{lv.name, func(l *Logger) { lv.print(l)("m") }},
+			method{lv.name + "f", func(l *Logger) { lv.printf(l)("%s", "m") }},
+			method{lv.name + "ln", func(l *Logger) { lv.println(l)("m") }})
+	}
+
+	flags := []struct {
+		name string
+		flag int
+	}{
+		{"Lshortfile", log.Lshortfile},
+		{"Llongfile", log.Llongfile},
+	}
+	for _, f := range flags {
+		for _, tt := range tests {
+			t.Run(f.name+"/"+tt.name, func(t *testing.T) {
+				l, buf := newTestLogger(t, LOG_DEBUG, WithFlags(f.flag|log.Lmsgprefix))
+				tt.log(l)
+				file := strings.SplitN(buf.String(), ":", 2)[0]
+				if filepath.Base(file) != "caller_test.go" || (f.flag == log.Llongfile) != filepath.IsAbs(file) {
+					t.Errorf("output %q doesn't report call site in caller_test.go", buf.String())
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package twigsnake
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// syncBuffer is bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Capture creates a logger with specified logging level and default settings writing to an in-memory buffer, runs fn
+// with it and returns everything written. It is meant for quick assertions in tests:
+//
+//	out := twigsnake.Capture(twigsnake.LOG_DEBUG, func(l *twigsnake.Logger) { l.Infoln("hi") })
+//
+// Only messages logged before fn returns are guaranteed to be captured: if fn starts goroutines which keep logging, it
+// must wait for them itself. Capture panics if lvl is invalid.
+func Capture(lvl int, fn func(*Logger)) string {
+	var buf syncBuffer
+	l, err := New(lvl, &buf)
+	if err != nil {
+		panic(err)
+	}
+	fn(l)
+	return buf.String()
+}
+
+// WithOutput redirects outputs of all levels to w, runs fn and restores previous outputs afterwards, even if fn panics.
+// It is handy for scoped capture of an existing logger's output, e.g. in tests, without constructing a new logger. Only
+// outputs of underlying standard loggers are swapped: sinks, output function and content router keep taking precedence
+// over them. WithOutput is not goroutine-safe with respect to logging done concurrently with fn: such messages may go
+// either to w or to the previous outputs, and calls of WithOutput itself must not overlap.
+func (l *Logger) WithOutput(w io.Writer, fn func()) {
+	prev := l.Outputs()
+	lgs := l.loggers()
+	defer func() {
+		for lvl, lg := range lgs {
+			lg.SetOutput(prev[lvl])
+		}
+	}()
+	for _, lg := range lgs {
+		lg.SetOutput(w)
+	}
+	fn()
+}
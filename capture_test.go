@@ -0,0 +1,82 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	out := Capture(LOG_INFO, func(l *Logger) {
+		l.SetFlags(log.Lmsgprefix)
+		l.Infoln("hi")
+		l.Debug("hidden")
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Warn("concurrent")
+			}()
+		}
+		wg.Wait()
+		l.Error("bye")
+	})
+	want := "[INFO] hi\n" + strings.Repeat("[WARN] concurrent\n", 10) + "[ERROR] bye\n"
+	if out != want {
+		t.Errorf("Capture() = %q, want %q", out, want)
+	}
+
+	// Default flags are kept unless fn changes them.
+	if out := Capture(LOG_DEBUG, func(l *Logger) { l.Debug("d") }); !strings.HasSuffix(out, " [DEBUG] d\n") {
+		t.Errorf("Capture() = %q, want timestamped debug line", out)
+	}
+}
+
+func TestCaptureInvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Capture with invalid level didn't panic")
+		}
+	}()
+	Capture(100, func(l *Logger) { t.Error("fn called") })
+}
+
+func TestWithOutput(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	var errs, scoped bytes.Buffer
+	l.ErrorLogger.SetOutput(&errs)
+
+	l.Info("before")
+	l.WithOutput(&scoped, func() {
+		l.Info("inside")
+		l.Error("inside")
+	})
+	l.Info("after")
+	l.Error("after")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic of fn not propagated")
+			}
+		}()
+		l.WithOutput(&scoped, func() {
+			l.Warn("panicking")
+			panic("boom")
+		})
+	}()
+	l.Warn("restored")
+
+	if got, want := scoped.String(), "[INFO] inside\n[ERROR] inside\n[WARN] panicking\n"; got != want {
+		t.Errorf("scoped output %q, want %q", got, want)
+	}
+	if got, want := buf.String(), "[INFO] before\n[INFO] after\n[WARN] restored\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+	if got, want := errs.String(), "[ERROR] after\n"; got != want {
+		t.Errorf("error output %q, want %q", got, want)
+	}
+}
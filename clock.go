@@ -0,0 +1,69 @@
+package twigsnake
+
+import (
+	"fmt"
+	"time"
+)
+
+// clockFunc wraps clock function so it can be kept in atomic.Value.
+type clockFunc struct {
+	now func() time.Time
+}
+
+// SetClock sets the function used to obtain current time for timestamps and all time-dependent features such as rate
+// limiting. It is mostly useful in tests, where frozen or simulated time makes output deterministic. Nil restores the
+// default time.Now. Start time of elapsed timestamps (see SetElapsedTimestamps) is reset to the new clock's current time.
+func (l *Logger) SetClock(clock func() time.Time) {
+	r := l.base()
+	r.clock.Store(clockFunc{clock})
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.start = now
+}
+
+// deterministicTime is the placeholder timestamp of all messages in deterministic mode.
+var deterministicTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SetDeterministic enables or disables deterministic mode meant for golden-file tests of output: every message gets
+// the same placeholder timestamp, 2000-01-01 00:00:00 UTC, whatever the format, and elapsed timestamps (see
+// SetElapsedTimestamps) are always zero, so output is byte-stable across runs. Only rendering is affected: the logger's
+// clock still drives time-dependent features such as rate limiting, so inject a clock (see SetClock) to make those
+// deterministic as well. Do not use it in production.
+func (l *Logger) SetDeterministic(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deterministic = enabled
+}
+
+// SetElapsedTimestamps switches text format between wall-clock timestamps and time elapsed since the logger was
+// created, e.g. "+1.234s", measured with the logger's clock. When enabled, elapsed time is printed in place of date and
+// time regardless of Ldate, Ltime and Lmicroseconds flags; other formats are not affected.
+func (l *Logger) SetElapsedTimestamps(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.elapsed = enabled
+}
+
+// now returns current time according to the logger's clock.
+func (l *Logger) now() time.Time {
+	if c, _ := l.clock.Load().(clockFunc); c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// LogAt prints message on given level with timestamp t instead of the current time, e.g. when replaying or backfilling
+// historical events. Arguments are handled in the same manner as log.Print. Messages are deduplicated and rate limited
+// like any other; since t has nothing to do with the current rate of messages, token buckets set with SetTokenBucket
+// are charged at the current time of the logger's clock. Zero t stands for the current time.
+func (l *Logger) LogAt(t time.Time, level int, v ...interface{}) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+	l.outputAt(level, fmt.Sprint(v...), nil, callerPC(1), t)
+}
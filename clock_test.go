@@ -0,0 +1,60 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	local := time.FixedZone("UTC+2", 2*60*60)
+	tests := []struct {
+		name  string
+		clock time.Time
+		opts  []Option
+		want  string
+	}{
+		{"date and time", testTime, []Option{WithFlags(log.Ldate | log.Ltime)}, "[INFO] 2021/03/05 14:30:15 m"},
+		{"microseconds", testTime, []Option{WithFlags(log.Ltime | log.Lmicroseconds)}, "[INFO] 14:30:15.123456 m"},
+		{"local time", testTime.In(local), []Option{WithFlags(log.Ltime)}, "[INFO] 16:30:15 m"},
+		{"UTC flag", testTime.In(local), []Option{WithFlags(log.Ltime | log.LUTC)}, "[INFO] 14:30:15 m"},
+		{"JSON", testTime, []Option{WithFormat(FormatJSON)},
+			`{"time":"2021-03-05T14:30:15Z","level":"info","msg":"m"}`},
+		{"logfmt", testTime, []Option{WithFormat(FormatLogfmt)}, "time=2021-03-05T14:30:15Z level=info msg=m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			clock := WithClock(func() time.Time { return tt.clock })
+			l, err := New(LOG_INFO, &buf, append([]Option{clock}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.Info("m")
+			l.Info("m")
+			if got := lines(&buf); len(got) != 2 || got[0] != tt.want || got[1] != tt.want {
+				t.Errorf("output %q, want %q twice", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetClockNil(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO, WithFormat(FormatJSON))
+	l.With().SetClock(nil)
+	before := time.Now().Add(-time.Second)
+	l.Info("m")
+	after := time.Now().Add(time.Second)
+
+	s := buf.String()
+	i := strings.Index(s, `"time":"`) + len(`"time":"`)
+	ts, err := time.Parse(time.RFC3339, s[i:i+strings.IndexByte(s[i:], '"')])
+	if err != nil {
+		t.Fatalf("can't parse time of %q: %v", s, err)
+	}
+	if ts.Before(before) || ts.After(after) {
+		t.Errorf("time %v isn't current", ts)
+	}
+}
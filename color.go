@@ -0,0 +1,22 @@
+package twigsnake
+
+// levelColors holds ANSI escape sequences coloring messages of every severity level: emergency, alert and critical in
+// bold red, error in red, warning in yellow, notice in cyan, informational in default color and debug in gray.
+var levelColors = [8]string{"\x1b[1;31m", "\x1b[1;31m", "\x1b[1;31m", "\x1b[31m", "\x1b[33m", "\x1b[36m", "", "\x1b[90m"}
+
+// colorReset is ANSI escape sequence restoring default color.
+const colorReset = "\x1b[0m"
+
+// colorFormatter renders entries the same way textFormatter does, colored according to their level with ANSI escape
+// sequences.
+type colorFormatter struct{}
+
+func (colorFormatter) Format(buf []byte, e *Entry) []byte {
+	color := levelColors[e.Level]
+	if color == "" {
+		return textFormatter{}.Format(buf, e)
+	}
+	buf = append(buf, color...)
+	buf = textFormatter{}.Format(buf, e)
+	return append(buf, colorReset...)
+}
@@ -0,0 +1,147 @@
+package twigsnake
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+)
+
+// Config is a snapshot of logger settings. Per-level settings are indexed by severity level.
+type Config struct {
+	Level         int
+	Prefixes      [8]string
+	Flags         [8]int
+	Outputs       [8]io.Writer
+	AlertLevel    int
+	AlertSink     io.Writer
+	CallerFunc    bool
+	CallerPackage bool
+	MaxDepth      int
+	LineEnding    string
+}
+
+// Config returns snapshot of current logger settings. Called on named logger or logger returned by With, it returns
+// settings of the logger they belong to, except for the level, which is their own.
+func (l *Logger) Config() Config {
+	r := l.base()
+	c := Config{
+		Level:   l.LogLevel(),
+		Outputs: l.Outputs(),
+	}
+	for lvl, lg := range l.loggers() {
+		c.Prefixes[lvl] = lg.Prefix()
+		c.Flags[lvl] = lg.Flags()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.AlertLevel, c.AlertSink = r.alertLevel, r.alertSink
+	c.CallerFunc, c.CallerPackage = r.callerFunc, r.callerPkg
+	c.MaxDepth = r.maxDepth
+	c.LineEnding = r.lineEnding
+	return c
+}
+
+// DiffConfig returns human-readable list of differences between settings of loggers a and b, one per line in a stable
+// order, or nil if there are none. Writers of non-comparable types are compared by identity (see sameWriter). Differing
+// writers are described by name if they have one, like files do, or by their address otherwise (see writerString).
+func DiffConfig(a, b *Logger) []string {
+	ca, cb := a.Config(), b.Config()
+	var diff []string
+	add := func(name string, va, vb interface{}) {
+		diff = append(diff, fmt.Sprintf("%s: %v != %v", name, va, vb))
+	}
+
+	if ca.Level != cb.Level {
+		add("level", levelNames[ca.Level], levelNames[cb.Level])
+	}
+	for lvl := LOG_EMERG; lvl <= LOG_DEBUG; lvl++ {
+		if ca.Prefixes[lvl] != cb.Prefixes[lvl] {
+			add("prefix["+levelNames[lvl]+"]", fmt.Sprintf("%q", ca.Prefixes[lvl]), fmt.Sprintf("%q", cb.Prefixes[lvl]))
+		}
+	}
+	for lvl := LOG_EMERG; lvl <= LOG_DEBUG; lvl++ {
+		if ca.Flags[lvl] != cb.Flags[lvl] {
+			add("flags["+levelNames[lvl]+"]", flagsString(ca.Flags[lvl]), flagsString(cb.Flags[lvl]))
+		}
+	}
+	for lvl := LOG_EMERG; lvl <= LOG_DEBUG; lvl++ {
+		if !sameWriter(ca.Outputs[lvl], cb.Outputs[lvl]) {
+			add("output["+levelNames[lvl]+"]", writerString(ca.Outputs[lvl]), writerString(cb.Outputs[lvl]))
+		}
+	}
+	if ca.AlertLevel != cb.AlertLevel {
+		add("alert level", ca.AlertLevel, cb.AlertLevel)
+	}
+	if !sameWriter(ca.AlertSink, cb.AlertSink) {
+		add("alert sink", writerString(ca.AlertSink), writerString(cb.AlertSink))
+	}
+	if ca.CallerFunc != cb.CallerFunc {
+		add("caller func", ca.CallerFunc, cb.CallerFunc)
+	}
+	if ca.CallerPackage != cb.CallerPackage {
+		add("caller package", ca.CallerPackage, cb.CallerPackage)
+	}
+	if ca.MaxDepth != cb.MaxDepth {
+		add("max depth", ca.MaxDepth, cb.MaxDepth)
+	}
+	if ca.LineEnding != cb.LineEnding {
+		add("line ending", fmt.Sprintf("%q", ca.LineEnding), fmt.Sprintf("%q", cb.LineEnding))
+	}
+	return diff
+}
+
+// sameWriter reports whether a and b are the same writer. Writers of non-comparable types are the same only if a and b
+// hold the very same value (see writerKey).
+func sameWriter(a, b io.Writer) bool {
+	return writerKey(a) == writerKey(b)
+}
+
+// writerString describes writer w for DiffConfig by its type and either its name, if it has Name method like *os.File
+// does, or its identity: the address of the value it points to or holds, or the value itself if it's comparable and not
+// a pointer, e.g. "*os.File(/var/log/app.log)" or "*bytes.Buffer(0xc000010000)".
+func writerString(w io.Writer) string {
+	if w == nil {
+		return "<nil>"
+	}
+	if n, ok := w.(interface{ Name() string }); ok {
+		return fmt.Sprintf("%T(%s)", w, n.Name())
+	}
+	if id, ok := writerKey(w).(writerID); ok {
+		return fmt.Sprintf("%T(%p)", w, id.p)
+	}
+	if reflect.TypeOf(w).Kind() == reflect.Ptr {
+		return fmt.Sprintf("%T(%p)", w, w)
+	}
+	return fmt.Sprintf("%T(%v)", w, w)
+}
+
+// flagNames lists standard log flags in the order of their values.
+var flagNames = []struct {
+	flag int
+	name string
+}{
+	{log.Ldate, "Ldate"},
+	{log.Ltime, "Ltime"},
+	{log.Lmicroseconds, "Lmicroseconds"},
+	{log.Llongfile, "Llongfile"},
+	{log.Lshortfile, "Lshortfile"},
+	{log.LUTC, "LUTC"},
+	{log.Lmsgprefix, "Lmsgprefix"},
+}
+
+// flagsString renders log flags as names joined with "|", e.g. "Ldate|Ltime".
+func flagsString(flag int) string {
+	var names []string
+	for _, f := range flagNames {
+		if flag&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	if len(names) == 0 {
+		return "0"
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,71 @@
+package twigsnake
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffConfig(t *testing.T) {
+	var buf bytes.Buffer
+	f, err := os.Create(filepath.Join(t.TempDir(), "app.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	a, err := New(LOG_INFO, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New(LOG_INFO, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffConfig(a, b); diff != nil {
+		t.Fatalf("equal loggers differ: %q", diff)
+	}
+
+	b.SetLogLevel(LOG_DEBUG)
+	if err := b.SetOutputFor(LOG_ERROR, f); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetOutputFor(LOG_WARN, f); err != nil {
+		t.Fatal(err)
+	}
+	b.SetAlertSink(LOG_CRIT, f)
+	b.SetLineEnding("\r\n")
+	want := []string{
+		"level: info != debug",
+		fmt.Sprintf("output[error]: *bytes.Buffer(%p) != *os.File(%s)", &buf, f.Name()),
+		fmt.Sprintf("output[warn]: *bytes.Buffer(%p) != *os.File(%s)", &buf, f.Name()),
+		"alert level: 0 != 2",
+		"alert sink: <nil> != *os.File(" + f.Name() + ")",
+		`line ending: "\n" != "\r\n"`,
+	}
+	for i := 0; i < 3; i++ { // the order must not vary between calls
+		if got := DiffConfig(a, b); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Fatalf("diff\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestWriterString(t *testing.T) {
+	fn := funcWriter(func(p []byte) (int, error) { return len(p), nil })
+	tests := []struct {
+		w    interface{ Write([]byte) (int, error) }
+		want string
+	}{
+		{nil, "<nil>"},
+		{os.Stderr, "*os.File(/dev/stderr)"},
+		{fn, fmt.Sprintf("twigsnake.funcWriter(%p)", writerKey(fn).(writerID).p)},
+	}
+	for _, tt := range tests {
+		if got := writerString(tt.w); got != tt.want {
+			t.Errorf("writerString(%T) = %q, want %q", tt.w, got, tt.want)
+		}
+	}
+}
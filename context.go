@@ -0,0 +1,91 @@
+package twigsnake
+
+import "context"
+
+// fieldsKey is the context key for structured fields attached with ContextWithFields.
+type fieldsKey struct{}
+
+// loggerKey is the context key for logger attached with NewContext.
+type loggerKey struct{}
+
+// NewContext returns a copy of parent carrying l, which can be retrieved later with FromContext.
+func (l *Logger) NewContext(parent context.Context) context.Context {
+	return context.WithValue(parent, loggerKey{}, l)
+}
+
+// FromContext returns logger attached to ctx with NewContext. If there is none, new logger discarding all messages is
+// returned, so the result is never nil and can be used right away.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return NewNop()
+}
+
+// ContextWithFields returns a copy of parent carrying given structured fields in addition to the ones parent already
+// carries. Context fields are appended to messages logged with context-aware methods such as LogAttrs.
+func ContextWithFields(parent context.Context, fields ...Field) context.Context {
+	prev := ContextFields(parent)
+	all := make([]Field, 0, len(prev)+len(fields))
+	all = append(all, prev...)
+	all = append(all, fields...)
+	return context.WithValue(parent, fieldsKey{}, all)
+}
+
+// ContextFields returns structured fields attached to ctx with ContextWithFields.
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]Field)
+	return fields
+}
+
+// LogAttrs prints msg on given level with structured fields taken from ctx followed by attrs. It mirrors slog's
+// Logger.LogAttrs and is the cheapest way to log structured data: level is checked before anything else and typed
+// fields need no key/value pairing. Messages with invalid level are handled according to SetInvalidLevelPolicy.
+// Informational and debug messages are subject to sampling set with SetContextSampler.
+func (l *Logger) LogAttrs(ctx context.Context, level int, msg string, attrs ...Field) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.ContextEnabled(ctx, level) {
+		return
+	}
+
+	// attrs are copied even without context fields, so that they don't escape and disabled calls don't allocate.
+	ctxFields := ContextFields(ctx)
+	fields := make([]Field, 0, len(ctxFields)+len(attrs))
+	fields = append(fields, ctxFields...)
+	fields = append(fields, attrs...)
+	l.output(level, msg, fields)
+}
+
+// SetContextSampler sets function deciding whether informational and debug messages logged with context-aware methods
+// (LogAttrs and slog handler, see SlogHandler) are printed for the request ctx belongs to. When fn returns false, these
+// levels are skipped entirely for that request, while messages of higher severity are unaffected. This allows head-based
+// sampling tied to trace decisions: requests flagged for tracing are logged fully, the rest only log what matters. Nil
+// fn, the default, disables sampling. fn is called for every such message which passes level check, so it must be
+// cheap and safe for concurrent use.
+func (l *Logger) SetContextSampler(fn func(ctx context.Context) bool) {
+	l.base().sampler.Store(samplerFunc{fn})
+}
+
+// samplerFunc wraps context sampler so it can be kept in atomic.Value.
+type samplerFunc struct {
+	sample func(ctx context.Context) bool
+}
+
+// ContextEnabled reports whether message of given level logged with context ctx would be printed, taking both
+// effective logging level (see EffectiveEnabled) and context sampler (see SetContextSampler) into account. It takes no
+// locks, so it is cheap enough to call for every message.
+func (l *Logger) ContextEnabled(ctx context.Context, level int) bool {
+	if !l.EffectiveEnabled(level) {
+		return false
+	}
+	if level < LOG_INFO {
+		return true
+	}
+	s, _ := l.base().sampler.Load().(samplerFunc)
+	return s.sample == nil || s.sample(ctx)
+}
@@ -0,0 +1,147 @@
+package twigsnake
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tracedKey is the context key marking requests sampled by tracedSampler.
+type tracedKey struct{}
+
+// tracedSampler samples requests whose context carries tracedKey.
+func tracedSampler(ctx context.Context) bool {
+	traced, _ := ctx.Value(tracedKey{}).(bool)
+	return traced
+}
+
+func TestLoggerContext(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	ctx := l.Named("http").NewContext(context.Background())
+	ctx = context.WithValue(ctx, tracedKey{}, true) // wrapping contexts keep the logger
+
+	FromContext(ctx).Info("request")
+	if got, want := buf.String(), "[INFO] request logger=http\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+
+	for _, ctx := range []context.Context{context.Background(), nil} {
+		nop := FromContext(ctx)
+		if nop == nil {
+			t.Fatal("FromContext returned nil")
+		}
+		for lvl, w := range nop.Outputs() {
+			if w != ioutil.Discard {
+				t.Errorf("level %d of logger without context writes to %v", lvl, w)
+			}
+		}
+		if nop.IsLevelEnabled(LOG_ALERT) {
+			t.Error("logger without context has alert level enabled")
+		}
+		nop.Emerg("discarded")
+	}
+}
+
+func TestSetContextSampler(t *testing.T) {
+	traced := context.WithValue(context.Background(), tracedKey{}, true)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		level   int
+		printed bool
+	}{
+		{"traced debug", traced, LOG_DEBUG, true},
+		{"traced info", traced, LOG_INFO, true},
+		{"untraced debug", context.Background(), LOG_DEBUG, false},
+		{"untraced info", context.Background(), LOG_INFO, false},
+		{"untraced notice", context.Background(), LOG_NOTICE, true},
+		{"untraced error", context.Background(), LOG_ERROR, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.Named("x").SetContextSampler(tracedSampler)
+			if got := l.ContextEnabled(tt.ctx, tt.level); got != tt.printed {
+				t.Errorf("ContextEnabled = %v, want %v", got, tt.printed)
+			}
+			l.LogAttrs(tt.ctx, tt.level, "m")
+			if got := buf.Len() > 0; got != tt.printed {
+				t.Errorf("printed = %v, want %v (output %q)", got, tt.printed, buf.String())
+			}
+		})
+	}
+}
+
+func TestContextEnabledLockFree(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetContextSampler(tracedSampler)
+	traced := context.WithValue(context.Background(), tracedKey{}, true)
+
+	// Writes hold the logger lock, so checks made meanwhile, e.g. by other goroutines, must not wait for it.
+	l.mu.Lock()
+	done := make(chan bool)
+	go func() {
+		done <- l.ContextEnabled(traced, LOG_DEBUG) && !l.ContextEnabled(context.Background(), LOG_DEBUG)
+	}()
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Error("ContextEnabled ignored the sampler")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ContextEnabled blocked on the logger lock")
+	}
+	l.mu.Unlock()
+
+	l.SetContextSampler(nil)
+	if !l.ContextEnabled(context.Background(), LOG_DEBUG) {
+		t.Error("ContextEnabled = false after the sampler was removed")
+	}
+}
+
+func TestLogAttrsContextFields(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	ctx := ContextWithFields(context.Background(), Field{"req", 7})
+	ctx = ContextWithFields(ctx, Field{"user", "ann"})
+	l.With("svc", "api").LogAttrs(ctx, LOG_WARN, "m", Field{"k", "v"})
+	if got, want := strings.TrimSpace(buf.String()), "[WARN] m svc=api req=7 user=ann k=v"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestLogAttrsDisabledAllocs(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_NOTICE)
+	ctx := context.Background()
+	allocs := testing.AllocsPerRun(100, func() {
+		l.LogAttrs(ctx, LOG_INFO, "request", String("method", "GET"), Int("status", 200))
+	})
+	if allocs != 0 {
+		t.Errorf("disabled LogAttrs made %v allocations, want 0", allocs)
+	}
+}
+
+func BenchmarkLogAttrs(b *testing.B) {
+	ctx := context.Background()
+	for _, lvl := range []int{LOG_INFO, LOG_WARN} {
+		name := "enabled"
+		if lvl < LOG_INFO {
+			name = "disabled"
+		}
+		b.Run(name+"/LogAttrs", func(b *testing.B) {
+			l, _ := New(lvl, ioutil.Discard)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.LogAttrs(ctx, LOG_INFO, "request", String("method", "GET"), Int("status", 200))
+			}
+		})
+		b.Run(name+"/Infow", func(b *testing.B) {
+			l, _ := New(lvl, ioutil.Discard)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Infow("request", "method", "GET", "status", 200)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package twigsnake
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Counts returns the number of messages printed so far on every severity level, indexed by level.
+func (l *Logger) Counts() [8]int64 {
+	r := l.base()
+	var counts [8]int64
+	for lvl := range counts {
+		counts[lvl] = atomic.LoadInt64(&r.counts[lvl])
+	}
+	return counts
+}
+
+// WorstLevel returns the most severe level of messages printed so far, or false if nothing has been printed yet.
+func (l *Logger) WorstLevel() (int, bool) {
+	for lvl, n := range l.Counts() {
+		if n > 0 {
+			return lvl, true
+		}
+	}
+	return 0, false
+}
+
+// SetCloseSummary makes Close print one-line summary of the run on given level before flushing, e.g.
+// "summary errors=3 warns=10 worst=ERROR": counts of messages of every level printed at least once and the most
+// severe of them. Invalid level (e.g. -1) disables the summary, which is the default.
+func (l *Logger) SetCloseSummary(level int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeSummary = checkLogLevel(level) == nil
+	r.summaryLevel = level
+}
+
+// printCloseSummary prints summary configured with SetCloseSummary, if any.
+func (l *Logger) printCloseSummary() {
+	l.mu.Lock()
+	enabled, level := l.closeSummary, l.summaryLevel
+	l.mu.Unlock()
+	if !enabled || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	counts := l.Counts()
+	s := "summary"
+	for lvl, n := range counts {
+		if n > 0 {
+			s += " " + levelNames[lvl] + "s=" + strconv.FormatInt(n, 10)
+		}
+	}
+	worst := "NONE"
+	if lvl, ok := l.WorstLevel(); ok {
+		worst = strings.ToUpper(levelNames[lvl])
+	}
+	l.output(level, s+" worst="+worst, nil)
+}
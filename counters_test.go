@@ -0,0 +1,64 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorstLevel(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_INFO)
+	if lvl, ok := l.WorstLevel(); ok {
+		t.Errorf("WorstLevel() = %d before logging, want none", lvl)
+	}
+
+	steps := []struct {
+		log   func()
+		worst int
+	}{
+		{func() { l.Info("i") }, LOG_INFO},
+		{func() { l.Debug("hidden") }, LOG_INFO}, // not printed, so not counted
+		{func() { l.Warn("w") }, LOG_WARN},
+		{func() { l.Named("db").Error("e") }, LOG_ERROR},
+		{func() { l.Notice("n") }, LOG_ERROR},
+	}
+	for i, s := range steps {
+		s.log()
+		if lvl, ok := l.WorstLevel(); !ok || lvl != s.worst {
+			t.Errorf("step %d: WorstLevel() = %d, %v; want %d", i, lvl, ok, s.worst)
+		}
+	}
+	if got, want := l.Counts(), [8]int64{LOG_ERROR: 1, LOG_WARN: 1, LOG_NOTICE: 1, LOG_INFO: 1}; got != want {
+		t.Errorf("Counts() = %v, want %v", got, want)
+	}
+}
+
+func TestCloseSummary(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		log   func(l *Logger)
+		want  []string
+	}{
+		{"disabled", -1, func(l *Logger) { l.Error("e") }, []string{"[ERROR] e"}},
+		{"nothing printed", LOG_NOTICE, func(l *Logger) {}, []string{"[NOTICE] summary worst=NONE"}},
+		{"counts", LOG_NOTICE, func(l *Logger) {
+			l.Warn("w1")
+			l.Error("e")
+			l.Warn("w2")
+		}, []string{"[WARN] w1", "[ERROR] e", "[WARN] w2", "[NOTICE] summary errors=1 warns=2 worst=ERROR"}},
+		{"below logging level", LOG_DEBUG, func(l *Logger) { l.Info("i") }, []string{"[INFO] i"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			l.SetCloseSummary(tt.level)
+			tt.log(l)
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package twigsnake
+
+import (
+	"strings"
+	"time"
+)
+
+// csvFormatter renders entries as comma separated values.
+type csvFormatter struct {
+	columns []string
+}
+
+// NewCSVFormatter returns formatter producing comma separated values with time (RFC 3339), level name and message
+// columns, followed by one column per given field key and the final "fields" column holding all remaining fields in
+// logfmt style (key=value pairs separated by spaces, values quoted when necessary). Values are quoted and escaped
+// according to RFC 4180, so commas, quotes and line breaks in messages are safe. Every destination receives a header
+// row before its first entry.
+func NewCSVFormatter(columns ...string) Formatter {
+	return &csvFormatter{columns: append([]string(nil), columns...)}
+}
+
+func (f *csvFormatter) Header(buf []byte) []byte {
+	buf = append(buf, "time,level,msg"...)
+	for _, c := range f.columns {
+		buf = append(buf, ',')
+		buf = appendCSV(buf, c)
+	}
+	return append(buf, ",fields"...)
+}
+
+func (f *csvFormatter) Format(buf []byte, e *Entry) []byte {
+	buf = e.Time.AppendFormat(buf, time.RFC3339)
+	buf = append(buf, ',')
+	buf = append(buf, e.LevelName()...)
+	buf = append(buf, ',')
+	buf = appendCSV(buf, e.Message)
+
+	used := make([]bool, len(e.Fields))
+	for _, c := range f.columns {
+		buf = append(buf, ',')
+		// When the key is repeated, the last field wins.
+		last := -1
+		for i := range e.Fields {
+			if e.Fields[i].Key == c {
+				used[i] = true
+				last = i
+			}
+		}
+		if last >= 0 {
+			buf = appendCSV(buf, string(appendValue(nil, e.Fields[last].Value, e.MaxDepth, e.BytesEncoding)))
+		}
+	}
+
+	var rest []byte
+	for i, fld := range e.Fields {
+		if used[i] {
+			continue
+		}
+		if len(rest) > 0 {
+			rest = append(rest, ' ')
+		}
+		rest = append(rest, fld.Key...)
+		rest = append(rest, '=')
+		rest = append(rest, quoteLogfmt(string(appendValue(nil, fld.Value, e.MaxDepth, e.BytesEncoding)))...)
+	}
+	buf = append(buf, ',')
+	return appendCSV(buf, string(rest))
+}
+
+// appendCSV appends s to buf as a CSV value, quoting it if necessary.
+func appendCSV(buf []byte, s string) []byte {
+	if s == "" || (!strings.ContainsAny(s, ",\"\r\n") && s[0] != ' ' && s[len(s)-1] != ' ') {
+		return append(buf, s...)
+	}
+	buf = append(buf, '"')
+	buf = append(buf, strings.Replace(s, `"`, `""`, -1)...)
+	return append(buf, '"')
+}
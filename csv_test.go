@@ -0,0 +1,61 @@
+package twigsnake
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func TestCSVHeaderPerWriter(t *testing.T) {
+	var a, b bytes.Buffer
+	l, _ := newTestLogger(t, LOG_DEBUG, WithFormat(FormatCSV))
+	l.InfoLogger.SetOutput(bufferWriter(&a))
+	l.ErrorLogger.SetOutput(bufferWriter(&b))
+	l.Infoln("first")
+	l.Errorln("second")
+	l.Infoln("third")
+
+	tests := []struct {
+		name string
+		buf  *bytes.Buffer
+		msgs []string
+	}{
+		{"info writer", &a, []string{"first", "third"}},
+		{"error writer", &b, []string{"second"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := csv.NewReader(strings.NewReader(tt.buf.String())).ReadAll()
+			if err != nil {
+				t.Fatalf("invalid CSV %q: %v", tt.buf.String(), err)
+			}
+			if len(records) != len(tt.msgs)+1 || strings.Join(records[0], ",") != "time,level,msg,fields" {
+				t.Fatalf("records = %q, want header followed by %d rows", records, len(tt.msgs))
+			}
+			for i, msg := range tt.msgs {
+				if got := records[i+1][2]; got != msg {
+					t.Errorf("row %d msg = %q, want %q", i+1, got, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVQuoting(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatCSV))
+	msgs := []string{"plain", "comma, inside", `say "hi"`, "multi\nline"}
+	for _, msg := range msgs {
+		l.Info(msg)
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	for i, msg := range msgs {
+		if got := records[i+1]; got[1] != "info" || got[2] != msg {
+			t.Errorf("row %d = %q, want level info and msg %q", i+1, got, msg)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package twigsnake
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dailyLayout is the date layout in names of files written by DailyRotatingWriter.
+const dailyLayout = "2006-01-02"
+
+// DailyRotatingWriter is io.Writer appending to a file named after the current local date, e.g. app-2021-03-05.log,
+// and switching to a new file when the calendar day changes, as observed at the time of Write. If the new file can't
+// be opened, e.g. because the disk is full, writing continues to the previous one, the error is returned along with
+// the written message and switching is retried with the next write. It is safe for concurrent use, so one writer can
+// be shared by all severity levels.
+type DailyRotatingWriter struct {
+	mu       sync.Mutex
+	dir      string
+	prefix   string
+	maxFiles int
+	now      func() time.Time
+	day      string
+	f        *os.File
+}
+
+// NewDailyRotatingWriter creates directory dir if necessary, opens (creating if necessary) file of the current date in
+// it for appending and returns writer rotating it daily. Files are named prefix-YYYY-MM-DD.log and are never deleted
+// unless retention is set with SetMaxFiles. The current date is told by now, which is time.Now if nil; other clocks are
+// mostly useful in tests simulating day boundaries.
+func NewDailyRotatingWriter(dir, prefix string, now func() time.Time) (*DailyRotatingWriter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if now == nil {
+		now = time.Now
+	}
+	w := &DailyRotatingWriter{dir: dir, prefix: prefix, now: now}
+	if err := w.open(w.now().Format(dailyLayout)); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// SetMaxFiles sets number of daily files to keep, including the current one: older files are deleted whenever a new
+// file is opened. Zero, the default, keeps all files.
+func (w *DailyRotatingWriter) SetMaxFiles(n int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.maxFiles = n
+}
+
+func (w *DailyRotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if day := w.now().Format(dailyLayout); day != w.day {
+		rotateErr = w.rotate(day)
+	}
+	n, err := w.f.Write(p)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Path returns path of the file currently written to.
+func (w *DailyRotatingWriter) Path() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.path(w.day)
+}
+
+// Close closes the current file. Writes after Close fail.
+func (w *DailyRotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+// rotate switches to the file of given day. If it can't be opened, the current file is kept, so writing continues to
+// it and rotation is retried with the next write. Must be called with w.mu held.
+func (w *DailyRotatingWriter) rotate(day string) error {
+	old := w.f
+	if err := w.open(day); err != nil {
+		return err
+	}
+	return old.Close()
+}
+
+// open opens file of given day for appending and deletes files beyond retention. Must be called with w.mu held.
+func (w *DailyRotatingWriter) open(day string) error {
+	f, err := os.OpenFile(w.path(day), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	w.f = f
+	w.day = day
+	w.prune()
+	return nil
+}
+
+// prune deletes the oldest daily files so that at most maxFiles are left. Files are recognized by their names, so
+// unrelated files in the directory are never touched. Must be called with w.mu held.
+func (w *DailyRotatingWriter) prune() {
+	if w.maxFiles <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(filepath.Join(w.dir, w.prefix+"-*.log"))
+	if err != nil {
+		return
+	}
+	var days []string
+	for _, m := range matches {
+		day := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(m), w.prefix+"-"), ".log")
+		if _, err := time.Parse(dailyLayout, day); err == nil {
+			days = append(days, day)
+		}
+	}
+	if len(days) <= w.maxFiles {
+		return
+	}
+	sort.Strings(days)
+	for _, day := range days[:len(days)-w.maxFiles] {
+		if day != w.day {
+			os.Remove(w.path(day))
+		}
+	}
+}
+
+// path returns path of the file of given day.
+func (w *DailyRotatingWriter) path(day string) string {
+	return filepath.Join(w.dir, w.prefix+"-"+day+".log")
+}
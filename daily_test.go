@@ -0,0 +1,212 @@
+package twigsnake
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock function returning time which tests set explicitly.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func TestDailyRotatingWriter(t *testing.T) {
+	day := func(d, h, m, s int) time.Time { return time.Date(2021, 3, d, h, m, s, 0, time.Local) }
+	tests := []struct {
+		name   string
+		writes []time.Time
+		want   map[string]string // file name -> contents
+	}{
+		{"single day", []time.Time{day(5, 0, 0, 0), day(5, 23, 59, 59)},
+			map[string]string{"app-2021-03-05.log": "0\n1\n"}},
+		{"midnight", []time.Time{day(5, 23, 59, 59), day(6, 0, 0, 0)},
+			map[string]string{"app-2021-03-05.log": "0\n", "app-2021-03-06.log": "1\n"}},
+		{"skipped days", []time.Time{day(5, 12, 0, 0), day(9, 12, 0, 0), day(9, 13, 0, 0)},
+			map[string]string{"app-2021-03-05.log": "0\n", "app-2021-03-09.log": "1\n2\n"}},
+		{"clock going back", []time.Time{day(6, 0, 0, 1), day(5, 23, 59, 59)},
+			map[string]string{"app-2021-03-05.log": "1\n", "app-2021-03-06.log": "0\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			clock := &fakeClock{tt.writes[0]}
+			w, err := NewDailyRotatingWriter(filepath.Join(dir, "logs"), "app", clock.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, at := range tt.writes {
+				clock.t = at
+				if _, err := w.Write([]byte{byte('0' + i), '\n'}); err != nil {
+					t.Fatalf("write %d failed: %v", i, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := readDir(t, filepath.Join(dir, "logs")); !equalFiles(got, tt.want) {
+				t.Errorf("files %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyRotatingWriterInitialFile(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{time.Date(2001, 1, 2, 3, 4, 5, 0, time.Local)}
+	w, err := NewDailyRotatingWriter(dir, "app", clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if got, want := w.Path(), filepath.Join(dir, "app-2001-01-02.log"); got != want {
+		t.Errorf("Path() = %q, want %q named after the injected clock", got, want)
+	}
+}
+
+func TestDailyRotatingWriterRetention(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app-2021-03-01.log", "app-notes.log", "other-2021-03-01.log"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock := &fakeClock{time.Date(2021, 3, 5, 12, 0, 0, 0, time.Local)}
+	w, err := NewDailyRotatingWriter(dir, "app", clock.now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetMaxFiles(2)
+	for d := 6; d <= 8; d++ {
+		clock.t = clock.t.AddDate(0, 0, 1)
+		if _, err := w.Write([]byte("x\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+
+	got := readDir(t, dir)
+	var names []string
+	for name := range got {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{"app-2021-03-07.log", "app-2021-03-08.log", "app-notes.log", "other-2021-03-01.log"}
+	if len(names) != len(want) {
+		t.Fatalf("files %q, want %q", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("files %q, want %q", names, want)
+			break
+		}
+	}
+}
+
+func TestDailyRotatingWriterClosed(t *testing.T) {
+	w, err := NewDailyRotatingWriter(t.TempDir(), "app", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("x")); err != os.ErrClosed {
+		t.Errorf("Write after Close returned %v, want %v", err, os.ErrClosed)
+	}
+}
+
+func TestDailyRotatingWriterOpenFailure(t *testing.T) {
+	tests := []struct {
+		name  string
+		block func(t *testing.T, dir, path string) (unblock func())
+	}{
+		{"unwritable directory", func(t *testing.T, dir, path string) func() {
+			if err := os.Chmod(dir, 0555); err != nil {
+				t.Fatal(err)
+			}
+			if f, err := os.Create(filepath.Join(dir, "probe")); err == nil {
+				f.Close()
+				os.Chmod(dir, 0755)
+				t.Skip("directory permissions are not enforced for this user")
+			}
+			return func() { os.Chmod(dir, 0755) }
+		}},
+		{"file name taken by directory", func(t *testing.T, dir, path string) func() {
+			if err := os.Mkdir(path, 0755); err != nil {
+				t.Fatal(err)
+			}
+			return func() { os.Remove(path) }
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			clock := &fakeClock{time.Date(2021, 3, 5, 23, 0, 0, 0, time.Local)}
+			w, err := NewDailyRotatingWriter(dir, "app", clock.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			clock.t = clock.t.Add(2 * time.Hour)
+			unblock := tt.block(t, dir, filepath.Join(dir, "app-2021-03-06.log"))
+			for _, msg := range []string{"0\n", "1\n"} {
+				if n, err := w.Write([]byte(msg)); err == nil || n != len(msg) {
+					t.Errorf("Write(%q) while the new file can't be opened = %d, %v; want %d and error", msg, n, err,
+						len(msg))
+				}
+			}
+			unblock()
+			if _, err := w.Write([]byte("2\n")); err != nil {
+				t.Fatalf("Write after the new file can be opened failed: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			want := map[string]string{"app-2021-03-05.log": "0\n1\n", "app-2021-03-06.log": "2\n"}
+			if got := readDir(t, dir); !equalFiles(got, want) {
+				t.Errorf("files %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// readDir returns contents of files in dir by their names.
+func readDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string, len(infos))
+	for _, fi := range infos {
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[fi.Name()] = string(b)
+	}
+	return files
+}
+
+// equalFiles reports whether a and b hold the same files with the same contents.
+func equalFiles(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, s := range a {
+		if t, ok := b[name]; !ok || t != s {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package twigsnake
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Debounce prints message on given level unless a message with the same key was printed by Debounce within interval,
+// which is handy for periodic status lines (e.g. queue depth) called far more often than they are worth logging. Unlike
+// deduplication, messages are matched by the explicit key rather than by their contents. Time is measured with the
+// logger's clock. Arguments are handled in the same manner as log.Print. Every distinct key is remembered for the
+// lifetime of the logger, so keys should come from a small fixed set.
+func (l *Logger) Debounce(level int, key string, interval time.Duration, v ...interface{}) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	r := l.base()
+	now := r.now().UnixNano()
+	last, loaded := r.debounced.LoadOrStore(key, &now)
+	if loaded {
+		p := last.(*int64)
+		prev := atomic.LoadInt64(p)
+		if now-prev < int64(interval) || !atomic.CompareAndSwapInt64(p, prev, now) {
+			return
+		}
+	}
+	l.output(level, fmt.Sprint(v...), nil)
+}
@@ -0,0 +1,33 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	clock := &fakeClock{testTime}
+	l, buf := newTestLogger(t, LOG_INFO, WithClock(clock.now))
+	step := func(d time.Duration, key string, v ...interface{}) {
+		clock.t = clock.t.Add(d)
+		l.Debounce(LOG_INFO, key, time.Minute, v...)
+	}
+
+	step(0, "queue", "depth ", 1)
+	step(time.Second, "queue", "depth ", 2)               // within interval
+	step(0, "workers", "workers ", 3)                     // other key has its own interval
+	step(59*time.Second, "queue", "depth ", 4)            // exactly one interval after the first one
+	step(30*time.Second, "queue", "depth ", 5)            // within interval of the previous printed one
+	step(30*time.Second, "queue", "depth ", 6)            // one interval later again
+	step(time.Hour, "queue", "depth ", 7)                 // long after
+	l.Debounce(LOG_DEBUG, "debug", time.Minute, "hidden") // disabled level isn't printed nor remembered
+	l.SetLogLevel(LOG_DEBUG)
+	l.Debounce(LOG_DEBUG, "debug", time.Minute, "debug")
+
+	want := []string{"[INFO] depth 1", "[INFO] workers 3", "[INFO] depth 4", "[INFO] depth 6", "[INFO] depth 7",
+		"[DEBUG] debug"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
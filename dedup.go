@@ -0,0 +1,89 @@
+package twigsnake
+
+import (
+	"fmt"
+	"time"
+)
+
+// dedupStreak tracks the streak of identical messages of a single level.
+type dedupStreak struct {
+	key     string    // message and fields of the streak, empty if there is none
+	since   time.Time // time of the message which started the streak
+	repeats int       // number of repeats dropped since the streak started or was last reported
+	last    Entry     // the latest repeat, the summary is based on
+}
+
+// SetDedup collapses consecutive identical messages of the same level: the first one is printed, while repeats arriving
+// within window from it are dropped and reported with a single "(repeated Nx)" line once a different message of that
+// level arrives, or when the logger is flushed or closed. Messages are identical if they have the same text and fields.
+// A repeat arriving after window has passed reports the streak so far and is printed, starting a new window, so that a
+// message repeated forever still shows up once per window. Non-positive window disables deduplication, which is the
+// default.
+func (l *Logger) SetDedup(window time.Duration) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushDedup()
+	if window < 0 {
+		window = 0
+	}
+	r.dedupWindow = window
+}
+
+// WithDedup collapses consecutive identical messages repeated within window (see SetDedup).
+func WithDedup(window time.Duration) Option {
+	return func(l *Logger) {
+		l.SetDedup(window)
+	}
+}
+
+// dedupe reports whether e repeats the current streak of its level and should be dropped. Otherwise the streak is
+// reported and e starts a new one. Must be called with l.mu held.
+func (l *Logger) dedupe(e *Entry) bool {
+	if l.dedupWindow <= 0 {
+		return false
+	}
+	key := dedupKey(e)
+	s := &l.dedup[e.Level]
+	if s.key == key && e.Time.Sub(s.since) < l.dedupWindow {
+		s.repeats++
+		s.last = *e
+		return true
+	}
+	l.reportStreak(s)
+	*s = dedupStreak{key: key, since: e.Time}
+	return false
+}
+
+// flushDedup reports and ends streaks of all levels. Must be called with l.mu held.
+func (l *Logger) flushDedup() {
+	for i := range l.dedup {
+		l.reportStreak(&l.dedup[i])
+		l.dedup[i] = dedupStreak{}
+	}
+}
+
+// reportStreak prints "(repeated Nx)" line for repeats of streak s dropped so far, if any. Must be called with l.mu
+// held.
+func (l *Logger) reportStreak(s *dedupStreak) {
+	if s.repeats == 0 {
+		return
+	}
+	summary := s.last
+	summary.Message = fmt.Sprintf("(repeated %dx)", s.repeats)
+	summary.Fields = nil
+	s.repeats = 0
+	l.emit(&summary)
+}
+
+// dedupKey returns text identifying message and fields of e.
+func dedupKey(e *Entry) string {
+	buf := append([]byte(nil), e.Message...)
+	for _, f := range e.Fields {
+		buf = append(buf, ' ')
+		buf = append(buf, f.Key...)
+		buf = append(buf, '=')
+		buf = appendValue(buf, f.Value, e.MaxDepth, e.BytesEncoding)
+	}
+	return string(buf)
+}
@@ -0,0 +1,77 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedup(t *testing.T) {
+	type msg struct {
+		after time.Duration // time passed since the previous message
+		log   func(l *Logger)
+	}
+	a := func(l *Logger) { l.Error("a") }
+	b := func(l *Logger) { l.Error("b") }
+	tests := []struct {
+		name string
+		msgs []msg
+		want []string // output after Close
+	}{
+		{"burst", []msg{{0, a}, {0, a}, {0, a}, {0, a}},
+			[]string{"[ERROR] a", "[ERROR] (repeated 3x)"}},
+		{"interleaved", []msg{{0, a}, {0, b}, {0, a}, {0, b}},
+			[]string{"[ERROR] a", "[ERROR] b", "[ERROR] a", "[ERROR] b"}},
+		{"streak ended by different message", []msg{{0, a}, {0, a}, {0, b}},
+			[]string{"[ERROR] a", "[ERROR] (repeated 1x)", "[ERROR] b"}},
+		{"window passed", []msg{{0, a}, {time.Second, a}, {30 * time.Second, a}, {time.Second, a}},
+			[]string{"[ERROR] a", "[ERROR] (repeated 1x)", "[ERROR] a", "[ERROR] (repeated 1x)"}},
+		{"levels tracked separately", []msg{{0, a}, {0, func(l *Logger) { l.Warn("a") }}, {0, a}},
+			[]string{"[ERROR] a", "[WARN] a", "[ERROR] (repeated 1x)"}},
+		{"fields make messages distinct", []msg{{0, func(l *Logger) { l.Errorw("a", "k", 1) }},
+			{0, func(l *Logger) { l.Errorw("a", "k", 2) }}, {0, func(l *Logger) { l.Errorw("a", "k", 2) }}},
+			[]string{"[ERROR] a k=1", "[ERROR] a k=2", "[ERROR] (repeated 1x)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := testTime
+			l, buf := newTestLogger(t, LOG_DEBUG, WithClock(func() time.Time { return now }),
+				WithDedup(10*time.Second))
+			for _, m := range tt.msgs {
+				now = now.Add(m.after)
+				m.log(l)
+			}
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupFlush(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithDedup(time.Minute))
+	l.Info("a")
+	l.Info("a")
+	if got := lines(buf); len(got) != 1 {
+		t.Fatalf("output %q before Flush, want single line", got)
+	}
+	l.Flush()
+	want := []string{"[INFO] a", "[INFO] (repeated 1x)"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output %q after Flush, want %q", got, want)
+	}
+
+	// Disabling deduplication reports the pending streak too.
+	buf.Reset()
+	l.Info("a")
+	l.Info("a")
+	l.SetDedup(0)
+	l.Info("a")
+	want = []string{"[INFO] a", "[INFO] (repeated 1x)", "[INFO] a"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package twigsnake
+
+import "io"
+
+// SetDetailOutput sets destination for details of messages logged with ErrorDetail. Nil writer makes details go to the
+// main output along with their messages.
+func (l *Logger) SetDetailOutput(w io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.detailOutput = w
+}
+
+// ErrorDetail prints concise error message shortMsg, keeping potentially long detail (stack trace, request dump, etc.)
+// aside. When detail output is set, only shortMsg goes to the main output, while the detail output receives an entry
+// with the same timestamp and prefix holding shortMsg followed by detail on the next lines. Otherwise the detail is
+// folded into the main output right under the message. Like other messages, it is subject to deduplication and rate
+// limiting, which consider shortMsg only. Message will appear on logging level twigsnake.LOG_ERROR and higher.
+func (l *Logger) ErrorDetail(shortMsg string, detail string) {
+	if !l.EffectiveEnabled(LOG_ERROR) {
+		return
+	}
+
+	r := l.base()
+	lg := r.loggers()[LOG_ERROR]
+	fields := l.withName(nil)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entry(lg, LOG_ERROR, shortMsg, fields)
+	r.addCaller(&e, 0, 1)
+	if !r.admit(&e, e.Time) {
+		return
+	}
+	full := e
+	full.Message = e.Message + "\n" + trimEOL(detail)
+	if r.detailOutput == nil {
+		r.emit(&full)
+		return
+	}
+
+	r.emit(&e)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = r.formatLine(*buf, r.formatter, &full)
+	r.write(r.formatter, r.detailOutput, LOG_ERROR, *buf)
+}
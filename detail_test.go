@@ -0,0 +1,48 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestErrorDetail(t *testing.T) {
+	tests := []struct {
+		name       string
+		separate   bool
+		wantMain   []string
+		wantDetail []string
+	}{
+		{"folded", false, []string{"[ERROR] failed", "trace line 1", "trace line 2 logger=db"}, nil},
+		{"separate output", true, []string{"[ERROR] failed logger=db"},
+			[]string{"[ERROR] failed", "trace line 1", "trace line 2 logger=db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			var detail bytes.Buffer
+			if tt.separate {
+				l.SetDetailOutput(&detail)
+			}
+			l.Named("db").ErrorDetail("failed", "trace line 1\ntrace line 2\n")
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.wantMain, "\n") {
+				t.Errorf("main output %q, want %q", got, tt.wantMain)
+			}
+			if got := lines(&detail); strings.Join(got, "\n") != strings.Join(tt.wantDetail, "\n") {
+				t.Errorf("detail output %q, want %q", got, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestErrorDetailCaller(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithFlags(log.Lshortfile|log.Lmsgprefix))
+	l.SetCallerFunc(true)
+	l.ErrorDetail("failed", "detail")
+	got := lines(buf)
+	if len(got) != 2 || !strings.HasPrefix(got[0], "detail_test.go:") ||
+		got[1] != "detail caller=twigsnake.TestErrorDetailCaller" {
+		t.Errorf("output %q, want call site and caller function of the test", got)
+	}
+}
@@ -79,7 +79,9 @@
 //		logger.DebugLogger.SetPrefix("")
 //
 //		// Finally, set our file as destination for debug messages
-//		logger.DebugLogger.SetOutput(debugLog)
+//		if err := logger.SetOutputFor(twigsnake.LOG_DEBUG, debugLog); err != nil {
+//			panic(err)
+//		}
 //
 //		// Log some stuff
 //		logger.Infoln("Regular message")
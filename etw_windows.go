@@ -0,0 +1,130 @@
+package twigsnake
+
+import (
+	"crypto/sha1"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	advapi32             = syscall.NewLazyDLL("advapi32.dll")
+	procEventRegister    = advapi32.NewProc("EventRegister")
+	procEventUnregister  = advapi32.NewProc("EventUnregister")
+	procEventWriteString = advapi32.NewProc("EventWriteString")
+)
+
+// etwLevels maps severity levels to ETW trace levels (critical, error, warning, information and verbose).
+var etwLevels = [8]uint8{1, 1, 1, 2, 3, 4, 4, 5}
+
+// etwGUID is Windows GUID structure.
+type etwGUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+// etwProviderGUID derives provider GUID from its name the same way .NET EventSource and TraceLogging do, so tools like
+// PerfView can subscribe to the provider by name (e.g. "*MyCompany-MyService").
+func etwProviderGUID(name string) etwGUID {
+	namespace := []byte{0x48, 0x2C, 0x2D, 0xB2, 0xC3, 0x90, 0x47, 0xC8, 0x87, 0xF8, 0x1A, 0x15, 0xBF, 0xC1, 0x30, 0xFB}
+	h := sha1.New()
+	h.Write(namespace)
+	for _, c := range utf16.Encode([]rune(strings.ToUpper(name))) {
+		h.Write([]byte{byte(c >> 8), byte(c)})
+	}
+	sum := h.Sum(nil)
+	sum[7] = sum[7]&0x0F | 0x50
+
+	g := etwGUID{
+		Data1: binary.LittleEndian.Uint32(sum[0:4]),
+		Data2: binary.LittleEndian.Uint16(sum[4:6]),
+		Data3: binary.LittleEndian.Uint16(sum[6:8]),
+	}
+	copy(g.Data4[:], sum[8:16])
+	return g
+}
+
+// etwProvider is registered ETW provider shared by writers of all levels.
+type etwProvider struct {
+	handle uint64
+	once   sync.Once
+}
+
+// args64 converts 64-bit value into syscall arguments: it takes two of them on 32-bit platforms.
+func args64(v uint64) []uintptr {
+	if unsafe.Sizeof(uintptr(0)) == 4 {
+		return []uintptr{uintptr(v), uintptr(v >> 32)}
+	}
+	return []uintptr{uintptr(v)}
+}
+
+// etwWriter writes every message as ETW string event of fixed trace level.
+type etwWriter struct {
+	p     *etwProvider
+	level uint8
+}
+
+func (w etwWriter) Write(b []byte) (int, error) {
+	msg, err := syscall.UTF16PtrFromString(strings.TrimRight(strings.Replace(string(b), "\x00", "", -1), "\r\n"))
+	if err != nil {
+		return 0, err
+	}
+	// EventWriteString(RegHandle, Level, Keyword, String)
+	args := args64(w.p.handle)
+	args = append(args, uintptr(w.level))
+	args = append(args, args64(0)...)
+	args = append(args, uintptr(unsafe.Pointer(msg)))
+	if r, _, _ := procEventWriteString.Call(args...); r != 0 {
+		return 0, syscall.Errno(r)
+	}
+	return len(b), nil
+}
+
+// Close unregisters ETW provider. It is safe to call it for writers of every level: provider is unregistered once.
+func (w etwWriter) Close() error {
+	var err error
+	w.p.once.Do(func() {
+		if r, _, _ := procEventUnregister.Call(args64(w.p.handle)...); r != 0 {
+			err = syscall.Errno(r)
+		}
+	})
+	return err
+}
+
+// NewETW creates new Logger instance with specified logging level emitting messages as Event Tracing for Windows events
+// of the provider with given name. Severity levels are mapped to ETW trace levels: emergency, alert and critical to
+// critical, error to error, warning to warning, notice and informational to information and debug to verbose. Provider
+// GUID is derived from its name like .NET EventSource does, so the provider can be enabled by name in PerfView, WPR or
+// logman. Underlying loggers have empty prefixes and no flags set, since ETW records time and level by itself.
+func NewETW(lvl int, provider string) (*Logger, error) {
+	lvl, ok, _ := applyLevelPolicy(lvl)
+	if !ok {
+		return nil, errInvalidLevel
+	}
+	if err := procEventRegister.Find(); err != nil {
+		return nil, err
+	}
+
+	guid := etwProviderGUID(provider)
+	p := &etwProvider{}
+	if r, _, _ := procEventRegister.Call(uintptr(unsafe.Pointer(&guid)), 0, 0, uintptr(unsafe.Pointer(&p.handle))); r != 0 {
+		return nil, syscall.Errno(r)
+	}
+
+	l, err := New(lvl, nil)
+	if err != nil {
+		etwWriter{p: p}.Close()
+		return nil, err
+	}
+	for i, lg := range l.loggers() {
+		lg.SetOutput(etwWriter{p, etwLevels[i]})
+		lg.SetPrefix("")
+		lg.SetFlags(0)
+	}
+	return l, nil
+}
@@ -0,0 +1,43 @@
+package twigsnake
+
+import "testing"
+
+func TestNewETWInvalidLevelPolicy(t *testing.T) {
+	defer SetInvalidLevelPolicy(PolicyError)
+
+	for _, p := range []Policy{PolicyError, PolicyIgnore} {
+		SetInvalidLevelPolicy(p)
+		if l, err := NewETW(100, "Twigsnake-Test"); err == nil || l != nil {
+			t.Errorf("policy %d: NewETW(100) = %v, %v; want error", p, l, err)
+		}
+	}
+
+	SetInvalidLevelPolicy(PolicyClamp)
+	l, err := NewETW(100, "Twigsnake-Test")
+	if err != nil {
+		t.Fatalf("NewETW(100) with clamp policy failed: %v", err)
+	}
+	defer l.Close()
+	if got := l.LogLevel(); got != LOG_DEBUG {
+		t.Errorf("level %d, want %d", got, LOG_DEBUG)
+	}
+}
+
+func TestETWProviderGUID(t *testing.T) {
+	// GUIDs .NET assigns to event sources of these names, as listed by dotnet-trace and PerfView.
+	tests := []struct {
+		name string
+		want etwGUID
+	}{
+		{"System.Runtime", etwGUID{0x49592c0f, 0x5a05, 0x516d, [8]byte{0xaa, 0x4b, 0xa6, 0x4e, 0x02, 0x02, 0x6c, 0x89}}},
+		{"Microsoft-Diagnostics-DiagnosticSource",
+			etwGUID{0xadb401e1, 0x5296, 0x51f8, [8]byte{0xc1, 0x25, 0x5f, 0xda, 0x75, 0x82, 0x61, 0x44}}},
+		// Names are case-insensitive.
+		{"system.runtime", etwGUID{0x49592c0f, 0x5a05, 0x516d, [8]byte{0xaa, 0x4b, 0xa6, 0x4e, 0x02, 0x02, 0x6c, 0x89}}},
+	}
+	for _, tt := range tests {
+		if got := etwProviderGUID(tt.name); got != tt.want {
+			t.Errorf("etwProviderGUID(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
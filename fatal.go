@@ -0,0 +1,71 @@
+package twigsnake
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// osExit terminates the program; it is a variable so that termination can be intercepted.
+var osExit = os.Exit
+
+// SetFatalLevel sets severity level of messages printed by Fatal, Fatalf and Fatalln, LOG_CRIT by default. Invalid level
+// is clamped if PolicyClamp is set with SetInvalidLevelPolicy and ignored otherwise.
+func (l *Logger) SetFatalLevel(level int) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok {
+		return
+	}
+	atomic.StoreInt32(&l.base().fatalLevel, int32(level))
+}
+
+// fatalSeverity returns severity level of Fatal, Fatalf and Fatalln, see SetFatalLevel.
+func (l *Logger) fatalSeverity() int {
+	return int(atomic.LoadInt32(&l.base().fatalLevel))
+}
+
+// Fatal prints message on the fatal level (see SetFatalLevel), flushes the logger (see Flush) and terminates the
+// program with os.Exit(1). Since the program terminates anyway, the message is printed regardless of logging level, just
+// like emergency messages are, and bypasses deduplication and rate limiting, so the reason of termination is always
+// recorded. Deferred functions are not run. Handles arguments in the same manner as log.Print.
+func (l *Logger) Fatal(v ...interface{}) {
+	v, fields := l.trailingFields(v)
+	l.outputFatal(fmt.Sprint(v...), fields)
+	l.exit()
+}
+
+// Fatalf is like Fatal, but handles arguments in the same manner as log.Printf. It calls os.Exit(1), so deferred
+// functions are not run.
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.outputFatal(fmt.Sprintf(format, v...), nil)
+	l.exit()
+}
+
+// Fatalln is like Fatal, but handles arguments in the same manner as log.Println. It calls os.Exit(1), so deferred
+// functions are not run.
+func (l *Logger) Fatalln(v ...interface{}) {
+	v, fields := l.trailingFields(v)
+	l.outputFatal(fmt.Sprintln(v...), fields)
+	l.exit()
+}
+
+// outputFatal prints message s of Fatal, Fatalf or Fatalln with given fields like output does, but without deduplication,
+// rate limiting and suppression of empty messages, which would leave no record of why the program terminated.
+func (l *Logger) outputFatal(s string, fields []Field) {
+	r := l.base()
+	lvl := l.fatalSeverity()
+	lg := r.loggers()[lvl]
+	fields = resolveLazy(l.withName(fields))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entry(lg, lvl, s, fields)
+	r.addCaller(&e, 0, 2)
+	r.emit(&e)
+}
+
+// exit flushes the logger and terminates the program with exit code 1.
+func (l *Logger) exit() {
+	l.base().Flush()
+	osExit(1)
+}
@@ -0,0 +1,124 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fatalEnv names environment variable which makes TestFatal run the Fatal call it names in a subprocess.
+const fatalEnv = "TWIGSNAKE_FATAL"
+
+func TestFatal(t *testing.T) {
+	if method := os.Getenv(fatalEnv); method != "" {
+		l, err := New(LOG_EMERG, os.Stdout, WithFlags(log.Lmsgprefix))
+		if err != nil {
+			os.Exit(2)
+		}
+		l.Named("x").SetFatalLevel(LOG_ALERT)
+		deferred := func() { os.Stdout.WriteString("deferred function run\n") }
+		defer deferred()
+		switch method {
+		case "Fatal":
+			l.Fatal("bye")
+		case "Fatalf":
+			l.Fatalf("%s", "bye")
+		case "Fatalln":
+			l.Fatalln("bye")
+		}
+		os.Exit(0)
+	}
+
+	for _, method := range []string{"Fatal", "Fatalf", "Fatalln"} {
+		t.Run(method, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestFatal$")
+			cmd.Env = append(os.Environ(), fatalEnv+"="+method)
+			var out bytes.Buffer
+			cmd.Stdout = &out
+			err := cmd.Run()
+			if e, ok := err.(*exec.ExitError); !ok || e.ExitCode() != 1 {
+				t.Fatalf("subprocess exited with %v, want exit status 1", err)
+			}
+			if got := strings.TrimSpace(out.String()); got != "[ALERT] bye" {
+				t.Errorf("subprocess printed %q, want %q", got, "[ALERT] bye")
+			}
+		})
+	}
+}
+
+func TestSetFatalLevel(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	var code int
+	osExit = func(c int) { code = c }
+
+	tests := []struct {
+		name  string
+		level int
+		want  string
+	}{
+		{"invalid level ignored", -100, "[CRIT] bye"},
+		{"emerg", LOG_EMERG, "[EMERG] bye"},
+		{"below logging level", LOG_DEBUG, "[DEBUG] bye"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code = 0
+			l, buf := newTestLogger(t, LOG_EMERG)
+			l.With().SetFatalLevel(tt.level)
+			l.Fatal("bye")
+			if got := strings.TrimSpace(buf.String()); got != tt.want || code != 1 {
+				t.Errorf("output %q, exit code %d; want %q, 1", got, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestFatalBypassesLimits(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	osExit = func(int) {}
+
+	tests := []struct {
+		name  string
+		limit func(l *Logger)
+		want  []string
+	}{
+		{"exhausted token bucket", func(l *Logger) { l.SetTokenBucket(LOG_CRIT, 1, 1) },
+			[]string{"[CRIT] bye", "[CRIT] bye", "[CRIT] "}},
+		{"deduplication", func(l *Logger) { l.SetDedup(time.Hour) },
+			[]string{"[CRIT] bye", "[CRIT] bye", "[CRIT] (repeated 1x)", "[CRIT] "}},
+		{"empty message suppression", func(l *Logger) { l.SetSkipEmpty(true) },
+			[]string{"[CRIT] bye", "[CRIT] bye", "[CRIT] bye", "[CRIT] "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			tt.limit(l)
+			l.Crit("bye")
+			l.Crit("bye")
+			l.Fatal("bye")
+			l.Fatalf("")
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFatalCaller(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	osExit = func(int) {}
+
+	l, buf := newTestLogger(t, LOG_DEBUG, WithCaller(true))
+	l.Fatal("bye")
+	l.Fatalf("%s", "bye")
+	l.Fatalln("bye")
+	for _, line := range lines(buf) {
+		if !strings.HasPrefix(line, "fatal_test.go:") {
+			t.Errorf("line %q doesn't start with call site in fatal_test.go", line)
+		}
+	}
+}
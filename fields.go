@@ -0,0 +1,464 @@
+package twigsnake
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMaxDepth is the default nesting depth up to which maps, slices, arrays, structs and pointers in structured field
+// values are rendered.
+const DefaultMaxDepth = 5
+
+// Markers substituted for values which are not rendered.
+const (
+	maxDepthMarker = "…(max depth)"
+	cycleMarker    = "…(cycle)"
+	missingValue   = "!MISSING"
+)
+
+// Field is a single piece of structured context attached to a message, rendered as key=value after the message text.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// String constructs field with string value.
+func String(key, value string) Field {
+	return Field{key, value}
+}
+
+// Int constructs field with integer value.
+func Int(key string, value int) Field {
+	return Field{key, value}
+}
+
+// Int64 constructs field with 64-bit integer value.
+func Int64(key string, value int64) Field {
+	return Field{key, value}
+}
+
+// Uint constructs field with unsigned integer value.
+func Uint(key string, value uint) Field {
+	return Field{key, value}
+}
+
+// Float64 constructs field with floating-point value, rendered in the shortest form representing it exactly, e.g.
+// 0.1, 1e+21, NaN or +Inf.
+func Float64(key string, value float64) Field {
+	return Field{key, value}
+}
+
+// Bool constructs field with boolean value.
+func Bool(key string, value bool) Field {
+	return Field{key, value}
+}
+
+// Time constructs field with time value, rendered in RFC 3339 format with fractional seconds. Text formats render it
+// like timestamps of their lines instead: with the layout set with SetTimeFormat or, if there is none, with the
+// precision of fractional seconds set with SetLevelTimePrecision for the message's level.
+func Time(key string, value time.Time) Field {
+	return Field{key, value}
+}
+
+// Any constructs field with arbitrary value. Nested containers are rendered up to the depth set with SetMaxDepth.
+func Any(key string, value interface{}) Field {
+	return Field{key, value}
+}
+
+// Err constructs field with key "error" holding err.
+func Err(err error) Field {
+	return Field{"error", err}
+}
+
+// Lazy constructs field whose value is computed by fn only when the message is actually printed, so expensive values
+// (e.g. serialized request bodies) cost nothing while their level is disabled. Any field value of type
+// func() interface{} is treated the same way. Since fn may be called at most once per message, or not at all, it must
+// be free of side effects.
+func Lazy(key string, fn func() interface{}) Field {
+	return Field{key, fn}
+}
+
+// resolveLazy returns fields with values of lazy fields computed. Fields are copied only if there are lazy ones.
+func resolveLazy(fields []Field) []Field {
+	for i, f := range fields {
+		if _, ok := f.Value.(func() interface{}); !ok {
+			continue
+		}
+		resolved := append([]Field(nil), fields...)
+		for j := i; j < len(resolved); j++ {
+			if fn, ok := resolved[j].Value.(func() interface{}); ok {
+				resolved[j].Value = fn()
+			}
+		}
+		return resolved
+	}
+	return fields
+}
+
+// SetMaxDepth limits nesting depth of rendered structured field values: containers (maps, slices, arrays, structs and
+// pointers) nested deeper than depth are replaced with "…(max depth)" marker. Regardless of the limit, references
+// pointing back to a value which is being rendered are replaced with "…(cycle)" marker, so self-referencing data can't
+// hang the logger. Negative depth is treated as zero, allowing only scalar values.
+func (l *Logger) SetMaxDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxDepth = depth
+}
+
+// BytesEncoding is the encoding of []byte structured field values.
+type BytesEncoding int
+
+// Encodings of []byte structured field values.
+const (
+	BytesBase64 BytesEncoding = iota // standard base64 encoding with padding, the default
+	BytesHex                         // lowercase hexadecimal encoding
+)
+
+// SetBytesEncoding sets how []byte structured field values are rendered by all formats. By default they are encoded with
+// standard base64 encoding, the conventional way to put binary data into structured logs; BytesHex is easier to read for
+// short values such as hashes. In JSON formats the encoded value is a string and nil slices are rendered as null.
+func (l *Logger) SetBytesEncoding(enc BytesEncoding) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bytesEnc = enc
+}
+
+// appendBytes appends b to buf encoded with enc.
+func appendBytes(buf []byte, b []byte, enc BytesEncoding) []byte {
+	var n int
+	if enc == BytesHex {
+		n = hex.EncodedLen(len(b))
+	} else {
+		n = base64.StdEncoding.EncodedLen(len(b))
+	}
+	if cap(buf)-len(buf) < n {
+		grown := make([]byte, len(buf), len(buf)+n)
+		copy(grown, buf)
+		buf = grown
+	}
+	dst := buf[len(buf) : len(buf)+n]
+	if enc == BytesHex {
+		hex.Encode(dst, b)
+	} else {
+		base64.StdEncoding.Encode(dst, b)
+	}
+	return buf[:len(buf)+n]
+}
+
+// SetFieldOrder makes structured fields with listed keys come first, in the given order, followed by the remaining
+// fields sorted by key. This helps to keep the most important keys in front when scanning logs by eye. Fields with the
+// same key keep their relative order. Empty list restores the default, where fields appear in the order they were
+// given.
+func (l *Logger) SetFieldOrder(keys []string) {
+	var order map[string]int
+	if len(keys) > 0 {
+		order = make(map[string]int, len(keys))
+		for i, k := range keys {
+			if _, dup := order[k]; !dup {
+				order[k] = i
+			}
+		}
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fieldOrder = order
+}
+
+// orderFields returns copy of fields sorted according to order: keys present in order come first by their rank, then
+// the rest sorted by key.
+func orderFields(fields []Field, order map[string]int) []Field {
+	sorted := append([]Field(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, iok := order[sorted[i].Key]
+		rj, jok := order[sorted[j].Key]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok || jok:
+			return iok
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
+
+// Emergw prints emergency message with structured context given as alternating keys and values. They will appear on any logging
+// level.
+func (l *Logger) Emergw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_EMERG) {
+		l.output(LOG_EMERG, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Alertw prints alert message with structured context given as alternating keys and values. They will appear on logging level
+// twigsnake.LOG_ALERT and higher.
+func (l *Logger) Alertw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_ALERT) {
+		l.output(LOG_ALERT, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Critw prints critical message with structured context given as alternating keys and values. They will appear on logging level
+// twigsnake.LOG_CRIT and higher.
+func (l *Logger) Critw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_CRIT) {
+		l.output(LOG_CRIT, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Errorw prints error message with structured context given as alternating keys and values. They will appear on logging level
+// twigsnake.LOG_ERROR and higher.
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_ERROR) {
+		l.output(LOG_ERROR, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Warnw prints warning message with structured context given as alternating keys and values. They will appear on logging level
+// twigsnake.LOG_WARN and higher.
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_WARN) {
+		l.output(LOG_WARN, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Noticew prints notification message with structured context given as alternating keys and values. They will appear on logging
+// level twigsnake.LOG_NOTICE and higher.
+func (l *Logger) Noticew(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_NOTICE) {
+		l.output(LOG_NOTICE, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Infow prints informational message with structured context given as alternating keys and values. They will appear on logging
+// level twigsnake.LOG_INFO and higher.
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_INFO) {
+		l.output(LOG_INFO, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// Debugw prints debugging message with structured context given as alternating keys and values. They will appear only on logging
+// level twigsnake.LOG_DEBUG.
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	if l.EffectiveEnabled(LOG_DEBUG) {
+		l.output(LOG_DEBUG, msg, fieldsFromPairs(keysAndValues))
+	}
+}
+
+// SetTrailingMapFields makes Print and Println style methods (Info, Infoln, etc.) treat trailing argument of type
+// map[string]interface{} as structured fields rendered after the message, sorted by key. This is a lightweight way to
+// add context without switching to the w-methods. Note that with this option enabled, a map logged as a regular last
+// argument becomes fields as well; to log it as part of the message, use the Printf style methods.
+func (l *Logger) SetTrailingMapFields(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&l.base().trailMap, v)
+}
+
+// trailingFields splits trailing map argument off v if SetTrailingMapFields is enabled, returning the remaining
+// arguments and fields made of the map.
+func (l *Logger) trailingFields(v []interface{}) ([]interface{}, []Field) {
+	if len(v) == 0 || atomic.LoadInt32(&l.base().trailMap) == 0 {
+		return v, nil
+	}
+	m, ok := v[len(v)-1].(map[string]interface{})
+	if !ok {
+		return v, nil
+	}
+	fields := make([]Field, 0, len(m))
+	for k, val := range m {
+		fields = append(fields, Field{k, val})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return v[:len(v)-1], fields
+}
+
+// fieldsFromPairs converts alternating keys and values into fields. Field values found in place of a key are taken as is.
+// Non-string keys are converted to strings; key without a value gets "!MISSING" value.
+func fieldsFromPairs(keysAndValues []interface{}) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i++ {
+		if f, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, Field{key, missingValue})
+			break
+		}
+		i++
+		fields = append(fields, Field{key, keysAndValues[i]})
+	}
+	return fields
+}
+
+// appendValue appends textual representation of structured field value v to buf, rendering nested containers up to
+// maxDepth levels deep and byte slices encoded with enc.
+func appendValue(buf []byte, v interface{}, maxDepth int, enc BytesEncoding) []byte {
+	r := valueRenderer{buf: buf, maxDepth: maxDepth, bytesEnc: enc}
+	r.render(reflect.ValueOf(v), 0)
+	return r.buf
+}
+
+// appendTextValue appends v, a field value of entry e, to buf like appendValue does, but with time values formatted
+// according to time settings of e (see fieldTimeLayout) and with control characters and line breaks escaped (see
+// escapeUnsafe), so that the value stays within a single line of text.
+func appendTextValue(buf []byte, v interface{}, e *Entry) []byte {
+	start := len(buf)
+	r := valueRenderer{buf: buf, maxDepth: e.MaxDepth, bytesEnc: e.BytesEncoding, timeLayout: fieldTimeLayout(e)}
+	r.render(reflect.ValueOf(v), 0)
+	return escapeUnsafe(r.buf, start)
+}
+
+// valueRenderer renders arbitrary values similarly to fmt's %v verb, but with bounded nesting depth and protection
+// against reference cycles.
+type valueRenderer struct {
+	buf        []byte
+	maxDepth   int
+	bytesEnc   BytesEncoding
+	timeLayout string // layout of time values, time.RFC3339Nano if empty
+	visiting   map[uintptr]bool
+}
+
+func (r *valueRenderer) render(v reflect.Value, depth int) {
+	if !v.IsValid() || isNilValue(v) {
+		r.buf = append(r.buf, "<nil>"...)
+		return
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			layout := r.timeLayout
+			if layout == "" {
+				layout = time.RFC3339Nano
+			}
+			r.buf = x.AppendFormat(r.buf, layout)
+			return
+		case []byte:
+			r.buf = appendBytes(r.buf, x, r.bytesEnc)
+			return
+		case error:
+			r.buf = append(r.buf, x.Error()...)
+			return
+		case fmt.Stringer:
+			r.buf = append(r.buf, x.String()...)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		r.buf = strconv.AppendBool(r.buf, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		r.buf = strconv.AppendInt(r.buf, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		r.buf = strconv.AppendUint(r.buf, v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		r.buf = strconv.AppendFloat(r.buf, v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Interface:
+		r.render(v.Elem(), depth)
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		r.renderContainer(v, depth)
+	default:
+		r.buf = append(r.buf, fmt.Sprint(v)...)
+	}
+}
+
+func (r *valueRenderer) renderContainer(v reflect.Value, depth int) {
+	if isNilValue(v) {
+		r.buf = append(r.buf, "<nil>"...)
+		return
+	}
+	if depth >= r.maxDepth {
+		r.buf = append(r.buf, maxDepthMarker...)
+		return
+	}
+
+	// Only pointers, maps and slices may form cycles; remember them while their contents are rendered.
+	if k := v.Kind(); k == reflect.Ptr || k == reflect.Map || (k == reflect.Slice && v.Len() > 0) {
+		ptr := v.Pointer()
+		if r.visiting[ptr] {
+			r.buf = append(r.buf, cycleMarker...)
+			return
+		}
+		if r.visiting == nil {
+			r.visiting = make(map[uintptr]bool)
+		}
+		r.visiting[ptr] = true
+		defer delete(r.visiting, ptr)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		r.render(v.Elem(), depth+1)
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		r.buf = append(r.buf, "map["...)
+		for i, k := range keys {
+			if i > 0 {
+				r.buf = append(r.buf, ' ')
+			}
+			r.render(k, depth+1)
+			r.buf = append(r.buf, ':')
+			r.render(v.MapIndex(k), depth+1)
+		}
+		r.buf = append(r.buf, ']')
+	case reflect.Slice, reflect.Array:
+		r.buf = append(r.buf, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				r.buf = append(r.buf, ' ')
+			}
+			r.render(v.Index(i), depth+1)
+		}
+		r.buf = append(r.buf, ']')
+	case reflect.Struct:
+		t := v.Type()
+		r.buf = append(r.buf, '{')
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				r.buf = append(r.buf, ' ')
+			}
+			r.buf = append(r.buf, t.Field(i).Name...)
+			r.buf = append(r.buf, ':')
+			r.render(v.Field(i), depth+1)
+		}
+		r.buf = append(r.buf, '}')
+	}
+}
+
+// isNilValue reports whether v holds nil pointer, map, slice, interface, channel or function.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Chan, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
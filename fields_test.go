@@ -0,0 +1,296 @@
+package twigsnake
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// node is a self-referencing structure for cycle detection tests.
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestSetMaxDepth(t *testing.T) {
+	loop := &node{Name: "a"}
+	loop.Next = loop
+	nested := map[string]interface{}{"a": []interface{}{1, map[string]int{"b": 2}}}
+
+	tests := []struct {
+		name  string
+		depth int
+		value interface{}
+		want  string
+	}{
+		{"scalar at zero depth", 0, 42, "v=42"},
+		{"container at zero depth", 0, []int{1}, "v=…(max depth)"},
+		{"within limit", 3, nested, "v=map[a:[1 map[b:2]]]"},
+		{"beyond limit", 2, nested, "v=map[a:[1 …(max depth)]]"},
+		{"cycle", 10, loop, "v={Name:a Next:…(cycle)}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.With().SetMaxDepth(tt.depth)
+			l.Infow("m", "v", tt.value)
+			if got := strings.TrimPrefix(strings.TrimSpace(buf.String()), "[INFO] m "); got != tt.want {
+				t.Errorf("rendered %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextFieldEscaping(t *testing.T) {
+	tests := []struct {
+		name       string
+		key, value interface{}
+		want       string
+	}{
+		{"plain", "k", "v", `k=v`},
+		{"line break in value", "k", "v\n2026/01/01 00:00:00 [EMERG] forged", `k=v\n2026/01/01 00:00:00 [EMERG] forged`},
+		{"carriage return in value", "k", "a\rb", `k=a\rb`},
+		{"line break in key", "k\n[EMERG] forged", "v", `k\n[EMERG] forged=v`},
+		{"line separator", "k", "a\u2028b", `k=a\u2028b`},
+		{"line break in nested value", "k", []string{"a\nb"}, `k=[a\nb]`},
+		{"non-ASCII kept", "k", "żółw", `k=żółw`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.Infow("m", tt.key, tt.value)
+			got := lines(buf)
+			if len(got) != 1 {
+				t.Fatalf("printed %d lines, want 1: %q", len(got), got)
+			}
+			if got := strings.TrimPrefix(got[0], "[INFO] m "); got != tt.want {
+				t.Errorf("rendered %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetBytesEncoding(t *testing.T) {
+	data := []byte{0, 1, 0xfe, 0xff, 'a', '\n'}
+	tests := []struct {
+		name   string
+		enc    BytesEncoding
+		decode func(s string) ([]byte, error)
+	}{
+		{"base64", BytesBase64, base64.StdEncoding.DecodeString},
+		{"hex", BytesHex, hex.DecodeString},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatJSON))
+			l.With().SetBytesEncoding(tt.enc)
+			l.Infow("m", "data", data, "none", []byte(nil))
+
+			var got struct {
+				Data string
+				None *string
+			}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if b, err := tt.decode(got.Data); err != nil || !bytes.Equal(b, data) {
+				t.Errorf("data %q decoded to %v, %v; want %v", got.Data, b, err, data)
+			}
+			if got.None != nil {
+				t.Errorf("nil slice rendered as %q, want null", *got.None)
+			}
+		})
+	}
+}
+
+func TestSetTrailingMapFields(t *testing.T) {
+	m := map[string]interface{}{"b": 2, "a": 1}
+	tests := []struct {
+		name    string
+		enabled bool
+		log     func(l *Logger)
+		want    string
+	}{
+		{"disabled", false, func(l *Logger) { l.Info("m ", m) }, "[INFO] m map[a:1 b:2]"},
+		{"Info", true, func(l *Logger) { l.Info("m", m) }, "[INFO] m a=1 b=2"},
+		{"Infoln", true, func(l *Logger) { l.Infoln("m", m) }, "[INFO] m a=1 b=2"},
+		{"With logger", true, func(l *Logger) { l.With("c", 3).Info("m", m) }, "[INFO] m c=3 a=1 b=2"},
+		{"map not last", true, func(l *Logger) { l.Info(m, " m") }, "[INFO] map[a:1 b:2] m"},
+		{"Infof keeps map in message", true, func(l *Logger) { l.Infof("m %v", m) }, "[INFO] m map[a:1 b:2]"},
+		{"other map type", true, func(l *Logger) { l.Info("m ", map[string]int{"a": 1}) }, "[INFO] m map[a:1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.Named("x").SetTrailingMapFields(tt.enabled)
+			tt.log(l)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypedFields(t *testing.T) {
+	ts := time.Date(2021, 3, 5, 14, 30, 15, 120000000, time.FixedZone("", -5*60*60))
+	tests := []struct {
+		field      Field
+		text, json string
+	}{
+		{Int("v", -42), "-42", "-42"},
+		{Int64("v", math.MinInt64), "-9223372036854775808", "-9223372036854775808"},
+		{Int64("v", 0), "0", "0"},
+		{Uint("v", math.MaxUint32), "4294967295", "4294967295"},
+		{Float64("v", 0), "0", "0"},
+		{Float64("v", -0.1), "-0.1", "-0.1"},
+		{Float64("v", 1e21), "1e+21", "1e+21"},
+		{Float64("v", 123456.789), "123456.789", "123456.789"},
+		{Float64("v", math.NaN()), "NaN", `"NaN"`},
+		{Float64("v", math.Inf(1)), "+Inf", `"+Inf"`},
+		{Float64("v", math.Inf(-1)), "-Inf", `"-Inf"`},
+		{Bool("v", true), "true", "true"},
+		{Bool("v", false), "false", "false"},
+		{Time("v", ts), "2021-03-05T14:30:15.12-05:00", `"2021-03-05T14:30:15.12-05:00"`},
+		{Time("v", time.Time{}), "0001-01-01T00:00:00Z", `"0001-01-01T00:00:00Z"`},
+		{String("v", "a b"), "a b", `"a b"`},
+		{Any("v", []int{1, -2}), "[1 -2]", "[1,-2]"},
+		{Any("v", nil), "<nil>", "null"},
+		{Err(errors.New("boom")), "boom", `"boom"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.LogAttrs(context.Background(), LOG_INFO, "m", tt.field)
+			if got, want := strings.TrimSuffix(buf.String(), "\n"), "[INFO] m "+tt.field.Key+"="+tt.text; got != want {
+				t.Errorf("text %q, want %q", got, want)
+			}
+
+			buf.Reset()
+			l.SetFormat(FormatJSON)
+			l.LogAttrs(context.Background(), LOG_INFO, "m", tt.field)
+			if got, want := buf.String(), `,"`+tt.field.Key+`":`+tt.json+"}\n"; !strings.HasSuffix(got, want) {
+				t.Errorf("JSON %q, want suffix %q", got, want)
+			}
+		})
+	}
+}
+
+func TestTimeFieldFormat(t *testing.T) {
+	ts := time.Date(2021, 3, 5, 14, 30, 15, 123456789, time.FixedZone("", -5*60*60))
+	tests := []struct {
+		name       string
+		setup      func(l *Logger)
+		text, json string
+	}{
+		{"default", func(l *Logger) {}, "2021-03-05T14:30:15.123456789-05:00", "2021-03-05T14:30:15.123456789-05:00"},
+		{"time format", func(l *Logger) { l.SetTimeFormat("2006-01-02 15:04") }, "2021-03-05 14:30",
+			"2021-03-05T14:30:15.123456789-05:00"},
+		{"second precision", func(l *Logger) { l.SetLevelTimePrecision(LOG_INFO, PrecisionSecond) },
+			"2021-03-05T14:30:15-05:00", "2021-03-05T14:30:15.123456789-05:00"},
+		{"milli precision", func(l *Logger) { l.SetLevelTimePrecision(LOG_INFO, PrecisionMilli) },
+			"2021-03-05T14:30:15.123-05:00", "2021-03-05T14:30:15.123456789-05:00"},
+		{"other level precision", func(l *Logger) { l.SetLevelTimePrecision(LOG_DEBUG, PrecisionSecond) },
+			"2021-03-05T14:30:15.123456789-05:00", "2021-03-05T14:30:15.123456789-05:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			tt.setup(l)
+			l.LogAttrs(context.Background(), LOG_INFO, "m", Time("v", ts))
+			if got, want := strings.TrimSuffix(buf.String(), "\n"), " m v="+tt.text; !strings.HasSuffix(got, want) {
+				t.Errorf("text %q, want suffix %q", got, want)
+			}
+
+			buf.Reset()
+			l.SetFormat(FormatJSON)
+			l.LogAttrs(context.Background(), LOG_INFO, "m", Time("v", ts))
+			if got, want := buf.String(), `,"v":"`+tt.json+`"}`+"\n"; !strings.HasSuffix(got, want) {
+				t.Errorf("JSON %q, want suffix %q", got, want)
+			}
+		})
+	}
+}
+
+func TestLazy(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	calls := 0
+	body := func() interface{} {
+		calls++
+		return map[string]int{"id": calls}
+	}
+
+	l.Debugw("hidden", "body", Lazy("body", body).Value)
+	l.LogAttrs(context.Background(), LOG_DEBUG, "hidden", Lazy("body", body))
+	if calls != 0 {
+		t.Fatalf("lazy value computed %d times for disabled messages", calls)
+	}
+
+	l.Infow("request", "body", body)
+	l.LogAttrs(context.Background(), LOG_WARN, "slow", Lazy("body", body), Int("ms", 1500))
+	l.With("body", Lazy("body", body).Value).Error("failed")
+	l.SetFormat(FormatJSON)
+	l.LogAttrs(context.Background(), LOG_INFO, "json", Lazy("body", body))
+
+	want := []string{"[INFO] request body=map[id:1]", "[WARN] slow body=map[id:2] ms=1500",
+		"[ERROR] failed body=map[id:3]", `{"time":"2021-03-05T14:30:15Z","level":"info","msg":"json","body":"map[id:4]"}`}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if calls != 4 {
+		t.Errorf("lazy value computed %d times, want once per printed message", calls)
+	}
+}
+
+// nilError is an error implementation with pointer receiver, for typed nil errors.
+type nilError struct{}
+
+func (*nilError) Error() string { return "nil error" }
+
+func TestNilAndPointerFields(t *testing.T) {
+	var err error
+	var typedErr error = (*nilError)(nil)
+	n := 7
+	pn := &n
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		text, json string
+	}{
+		{"nil interface", nil, "<nil>", "null"},
+		{"nil error", err, "<nil>", "null"},
+		{"typed nil error", typedErr, "<nil>", "null"},
+		{"nil struct pointer", (*node)(nil), "<nil>", "null"},
+		{"nil slice", []string(nil), "<nil>", "null"},
+		{"nil map", map[string]int(nil), "<nil>", "null"},
+		{"nil field of struct", node{Name: "a"}, "{Name:a Next:<nil>}", `"{Name:a Next:<nil>}"`},
+		{"pointer to int", &n, "7", "7"},
+		{"pointer to pointer", &pn, "7", "7"},
+		{"pointer to struct", &node{Name: "a", Next: &node{Name: "b"}}, "{Name:a Next:{Name:b Next:<nil>}}",
+			`"{Name:a Next:{Name:b Next:<nil>}}"`},
+		{"empty slice", []string{}, "[]", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.Infow("m", "v", tt.value)
+			if got, want := strings.TrimSuffix(buf.String(), "\n"), "[INFO] m v="+tt.text; got != want {
+				t.Errorf("text %q, want %q", got, want)
+			}
+
+			buf.Reset()
+			l.SetFormat(FormatJSON)
+			l.Infow("m", "v", tt.value)
+			if got, want := buf.String(), `,"v":`+tt.json+"}\n"; !strings.HasSuffix(got, want) {
+				t.Errorf("JSON %q, want suffix %q", got, want)
+			}
+		})
+	}
+}
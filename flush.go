@@ -0,0 +1,123 @@
+package twigsnake
+
+import (
+	"io"
+	"os"
+)
+
+// flusher is implemented by writers buffering data, such as bufio.Writer.
+type flusher interface {
+	Flush() error
+}
+
+// Flush reports repeats of messages collapsed by SetDedup, waits until all messages queued in asynchronous mode are
+// written, then flushes every destination which implements Flush() error (bufio.Writer, for instance): level outputs,
+// alert sink, detail output and sinks. Writers shared by several destinations are flushed once, and plain writers are
+// left alone, so for them Flush is a no-op. The first error encountered is returned. Called on named logger or logger
+// returned by With, it flushes the logger they belong to.
+func (l *Logger) Flush() error {
+	if l.root != nil {
+		return l.root.Flush()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.flushDedup()
+	l.drainAsync()
+	var first error
+	for _, w := range l.writers() {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// Close prints summary set up with SetCloseSummary, flushes the logger (see Flush), stops the asynchronous writer, if
+// any, and closes every destination which implements io.Closer, except standard output and standard error. Writers
+// shared by several destinations are closed once. The first error encountered is returned. The logger must not be used
+// after Close. Called on named logger or logger returned by With, it closes the logger they belong to, so all of them
+// must not be used afterwards.
+func (l *Logger) Close() error {
+	if l.root != nil {
+		return l.root.Close()
+	}
+	l.mu.Lock()
+	l.flushDedup()
+	l.mu.Unlock()
+	l.printCloseSummary()
+	first := l.Flush()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.async != nil {
+		close(l.async)
+		<-l.asyncDone
+		l.async = nil
+	}
+	for _, w := range l.writers() {
+		if err := closeWriter(w); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// closeWriter closes w if it implements io.Closer, unless it is standard output or standard error, which outlive any
+// logger.
+func closeWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// drainAsync waits until the asynchronous queue, if any, is empty. Must be called with l.mu held, which keeps new
+// writes from being queued meanwhile.
+func (l *Logger) drainAsync() {
+	if l.async == nil {
+		return
+	}
+	size := cap(l.async)
+	close(l.async)
+	<-l.asyncDone
+	l.async = make(chan asyncWrite, size)
+	l.asyncDone = make(chan struct{})
+	go l.runAsync(l.async, l.asyncDone)
+}
+
+// writers returns all distinct destinations the logger writes to. Writers are told apart by identity (see writerKey), so
+// a writer shared by several destinations is returned once, while distinct writers of the same type are all returned.
+// Must be called with l.mu held.
+func (l *Logger) writers() []io.Writer {
+	var ws []io.Writer
+	seen := make(map[interface{}]bool)
+	add := func(w io.Writer) {
+		if w != nil && !seen[writerKey(w)] {
+			seen[writerKey(w)] = true
+			ws = append(ws, w)
+		}
+	}
+
+	for lvl, w := range l.Outputs() {
+		if l.outputFunc != nil {
+			w = l.outputFunc(lvl)
+		}
+		add(w)
+	}
+	add(l.alertSink)
+	add(l.detailOutput)
+	add(l.auditOutput)
+	for _, s := range l.sinks {
+		add(s.Writer)
+	}
+	for _, s := range l.exports {
+		add(s.Writer)
+	}
+	return ws
+}
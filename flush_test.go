@@ -0,0 +1,81 @@
+package twigsnake
+
+import (
+	"errors"
+	"testing"
+)
+
+// finalizer is a writer recording calls of Flush and Close.
+type finalizer struct {
+	flushes, closes int
+	err             error
+}
+
+func (f *finalizer) Write(p []byte) (int, error) { return len(p), nil }
+func (f *finalizer) Flush() error                { f.flushes++; return f.err }
+func (f *finalizer) Close() error                { f.closes++; return f.err }
+
+// valueFinalizer is a finalizer of non-comparable type.
+type valueFinalizer struct {
+	f    *finalizer
+	tags []string
+}
+
+func (v valueFinalizer) Write(p []byte) (int, error) { return v.f.Write(p) }
+func (v valueFinalizer) Flush() error                { return v.f.Flush() }
+func (v valueFinalizer) Close() error                { return v.f.Close() }
+
+func TestFlushAndClose(t *testing.T) {
+	shared, alert := &finalizer{}, &finalizer{}
+	va, vb := &finalizer{}, &finalizer{}
+
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetOutput(shared)
+	l.SetAlertSink(LOG_ALERT, alert)
+	l.ErrorLogger.SetOutput(valueFinalizer{f: va})
+	l.WarningLogger.SetOutput(valueFinalizer{f: vb})
+
+	if err := l.With("k", "v").Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := l.Named("child").Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		f    *finalizer
+	}{
+		{"writer shared by levels", shared},
+		{"alert sink", alert},
+		{"first writer of non-comparable type", va},
+		{"second writer of non-comparable type", vb},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Close flushes as well, so every writer is flushed twice: by Flush and by Close.
+			if tt.f.flushes != 2 || tt.f.closes != 1 {
+				t.Errorf("flushes = %d, closes = %d; want 2 and 1", tt.f.flushes, tt.f.closes)
+			}
+		})
+	}
+}
+
+func TestFlushError(t *testing.T) {
+	want := errors.New("disk full")
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetOutput(&finalizer{err: want})
+	if err := l.Flush(); err != want {
+		t.Errorf("Flush() = %v, want %v", err, want)
+	}
+}
+
+func TestFlushPlainWriter(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	if err := l.Flush(); err != nil {
+		t.Errorf("Flush() = %v, want nil", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,326 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxPooledBuffer is the capacity above which line buffers are not returned to the pool, so that a single huge message
+// doesn't pin memory forever.
+const maxPooledBuffer = 64 << 10
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// getBuffer returns empty line buffer from the pool.
+func getBuffer() *[]byte {
+	b := bufPool.Get().(*[]byte)
+	*b = (*b)[:0]
+	return b
+}
+
+// putBuffer returns line buffer to the pool. Buffer must not be used afterwards.
+func putBuffer(b *[]byte) {
+	if cap(*b) <= maxPooledBuffer {
+		bufPool.Put(b)
+	}
+}
+
+// Entry describes single message passed to a Formatter.
+type Entry struct {
+	Time    time.Time
+	Level   int
+	Message string  // rendered message without trailing line break
+	Fields  []Field // structured context in order of appearance
+	Prefix  string  // prefix of the level's standard logger, sanitized with control characters escaped
+	Flags   int     // output flags of the level's standard logger
+	File    string  // caller file, resolved only if Flags ask for it
+	Line    int     // caller line, resolved only if Flags ask for it
+
+	// MaxDepth is the nesting limit for rendering field values, as set by SetMaxDepth.
+	MaxDepth int
+
+	// BytesEncoding is the encoding of []byte field values, as set by SetBytesEncoding.
+	BytesEncoding BytesEncoding
+
+	// TimePrecision is the precision of timestamps set by SetLevelTimePrecision, zero if timestamps follow Flags only.
+	TimePrecision Precision
+
+	// TimeFormat is the layout of timestamps set by SetTimeFormat, empty if timestamps follow Flags and TimePrecision.
+	TimeFormat string
+
+	// Facility is the syslog facility code set by SetFacility.
+	Facility int
+
+	// QuoteMessage asks text formats to quote the message, as set by SetQuoteMessage.
+	QuoteMessage bool
+
+	// Start is the time elapsed timestamps are counted from, zero unless SetElapsedTimestamps is enabled.
+	Start time.Time
+
+	// Names of the core keys for structured formats, as set by SetTimeKey, SetLevelKey and SetMessageKey. Empty names
+	// stand for DefaultTimeKey, DefaultLevelKey and DefaultMessageKey respectively.
+	TimeKey, LevelKey, MessageKey string
+
+	// Audit marks entries printed with Logger.Audit, which have no severity of their own: Level is LOG_EMERG, so that
+	// they pass every level filter, but formats name their level "audit" (see LevelName) and syslog formats report them
+	// as notices of the log audit facility.
+	Audit bool
+}
+
+// LevelName returns name of the entry's level as printed by structured formats, e.g. "error", or "audit" for audit
+// entries.
+func (e *Entry) LevelName() string {
+	if e.Audit {
+		return auditLevelName
+	}
+	return levelNames[e.Level]
+}
+
+// Formatter renders log entries. Format appends representation of e to buf and returns the extended buffer; line
+// ending is appended by the logger afterwards, so it must not be included. Formatter must be safe for concurrent use.
+type Formatter interface {
+	Format(buf []byte, e *Entry) []byte
+}
+
+// headerFormatter is implemented by formatters which need a header line written to every destination before the
+// first entry.
+type headerFormatter interface {
+	Header(buf []byte) []byte
+}
+
+// Format selects one of built-in formatters.
+type Format int
+
+// Built-in formats:
+const (
+	FormatText       Format = iota // Classic log.Logger style lines: prefix, timestamp, message and key=value fields
+	FormatCSV                      // Comma separated values: time, level, message and fields, preceded by a header row
+	FormatJSON                     // JSON object per line: time (RFC 3339), level name, message and fields
+	FormatColor                    // FormatText colored according to severity with ANSI escape sequences, for terminals
+	FormatCloudWatch               // JSON object per line with keys Amazon CloudWatch Logs Insights recognizes natively
+	FormatLogfmt                   // logfmt line: time (RFC 3339), level name, message and fields as key=value pairs
+	FormatRFC5424                  // RFC 5424 syslog message with local hostname and program name, see NewRFC5424Formatter
+)
+
+// Formatter returns new instance of the built-in formatter. Unknown formats fall back to FormatText.
+func (f Format) Formatter() Formatter {
+	switch f {
+	case FormatCSV:
+		return NewCSVFormatter()
+	case FormatJSON:
+		return jsonFormatter{}
+	case FormatColor:
+		return colorFormatter{}
+	case FormatCloudWatch:
+		return cloudWatchFormatter{}
+	case FormatLogfmt:
+		return logfmtFormatter{}
+	case FormatRFC5424:
+		return NewRFC5424Formatter("", "")
+	}
+	return textFormatter{}
+}
+
+// SetFormat switches logger to one of the built-in formats.
+func (l *Logger) SetFormat(f Format) {
+	l.SetFormatter(f.Formatter())
+}
+
+// SetFormatter sets custom formatter for all levels. Nil formatter restores the default FormatText. It is safe to call
+// while other goroutines are logging: formatter is swapped under the same lock which serializes writes, so every message
+// is rendered and written entirely by either the old or the new formatter, and writers receive the new formatter's
+// header (if any) before its first line. Called on named logger, it changes the formatter of the logger Named was
+// originally called on.
+func (l *Logger) SetFormatter(f Formatter) {
+	if f == nil {
+		f = textFormatter{}
+	}
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.formatter = f
+	r.headerDone = nil
+}
+
+// SetTimeFormat sets layout of timestamps in text formats, e.g. time.RFC3339. The layout replaces date and time parts
+// chosen by Ldate, Ltime and Lmicroseconds flags, as well as precision set with SetLevelTimePrecision, but timestamps
+// are still printed only if at least one of these flags is set; LUTC flag applies as usual. Empty layout restores the
+// default behavior.
+func (l *Logger) SetTimeFormat(layout string) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeFormat = layout
+}
+
+// SetQuoteMessage enables or disables quoting of messages in text formats: when enabled, message is printed as Go
+// quoted string, with quotes, backslashes, line breaks and non-printable characters escaped, so that tools splitting
+// lines on whitespace see it as a single token. Prefix, timestamp and fields are not affected. It is disabled by
+// default.
+func (l *Logger) SetQuoteMessage(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quoteMsg = enabled
+}
+
+// textFormatter renders entries the same way log.Logger does it: header (prefix, timestamp and caller file) followed
+// by the message and fields as key=value pairs. Control characters and line breaks in field keys and values are escaped
+// (see sanitizePrefix), so fields can't split the line and forge entries. Stack fields are rendered last, one frame per
+// line.
+type textFormatter struct{}
+
+func (textFormatter) Format(buf []byte, e *Entry) []byte {
+	buf = formatHeader(buf, e)
+	if e.QuoteMessage {
+		buf = strconv.AppendQuote(buf, e.Message)
+	} else {
+		buf = append(buf, e.Message...)
+	}
+	hasStack := false
+	for _, f := range e.Fields {
+		if _, ok := f.Value.(Stack); ok {
+			hasStack = true
+			continue
+		}
+		buf = append(buf, ' ')
+		start := len(buf)
+		buf = escapeUnsafe(append(buf, f.Key...), start)
+		buf = append(buf, '=')
+		buf = appendTextValue(buf, f.Value, e)
+	}
+	if hasStack {
+		for _, f := range e.Fields {
+			if s, ok := f.Value.(Stack); ok {
+				buf = appendStackLines(buf, f.Key, s)
+			}
+		}
+	}
+	return buf
+}
+
+// formatHeader appends prefix, timestamp and caller file of e according to its flags, exactly like log.Logger does.
+func formatHeader(buf []byte, e *Entry) []byte {
+	flag := e.Flags
+	if flag&log.Lmsgprefix == 0 {
+		buf = append(buf, e.Prefix...)
+	}
+	if !e.Start.IsZero() {
+		buf = append(buf, '+')
+		buf = strconv.AppendFloat(buf, e.Time.Sub(e.Start).Seconds(), 'f', 3, 64)
+		buf = append(buf, "s "...)
+	} else if flag&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		t := e.Time
+		if flag&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if e.TimeFormat != "" {
+			buf = t.AppendFormat(buf, e.TimeFormat)
+			buf = append(buf, ' ')
+		} else {
+			if flag&log.Ldate != 0 {
+				buf = t.AppendFormat(buf, "2006/01/02 ")
+			}
+			if flag&log.Lmicroseconds != 0 {
+				buf = t.AppendFormat(buf, "15:04:05.000000 ")
+			} else if flag&log.Ltime != 0 && e.TimePrecision > 0 {
+				buf = t.AppendFormat(buf, timeLayouts[e.TimePrecision])
+			} else if flag&log.Ltime != 0 {
+				buf = t.AppendFormat(buf, "15:04:05 ")
+			}
+		}
+	}
+	if flag&(log.Lshortfile|log.Llongfile) != 0 {
+		file := e.File
+		if flag&log.Lshortfile != 0 {
+			for i := len(file) - 1; i > 0; i-- {
+				if file[i] == '/' {
+					file = file[i+1:]
+					break
+				}
+			}
+		}
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(e.Line), 10)
+		buf = append(buf, ": "...)
+	}
+	if flag&log.Lmsgprefix != 0 {
+		buf = append(buf, e.Prefix...)
+	}
+	return buf
+}
+
+// callerNeeded reports whether flags ask for caller file and line.
+func callerNeeded(flag int) bool {
+	return flag&(log.Lshortfile|log.Llongfile) != 0
+}
+
+// sanitizePrefix returns prefix with control characters, including line breaks, ANSI escape sequences' ESC and Unicode
+// line and paragraph separators, replaced with their Go escapes such as "\n" or "\x1b". Prefixes often come from
+// configuration or other untrusted input, and unescaped they could split a line and forge log entries or hijack the
+// terminal. Prefixes without such characters are returned as is, without allocating.
+func sanitizePrefix(prefix string) string {
+	i := strings.IndexFunc(prefix, isUnsafeRune)
+	if i < 0 {
+		return prefix
+	}
+	var b strings.Builder
+	b.WriteString(prefix[:i])
+	for _, r := range prefix[i:] {
+		if isUnsafeRune(r) {
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// escapeUnsafe escapes unsafe characters in buf[start:] the same way sanitizePrefix does and returns the resulting
+// buffer. Bytes of invalid UTF-8 sequences are kept as they are.
+func escapeUnsafe(buf []byte, start int) []byte {
+	i := bytes.IndexFunc(buf[start:], isUnsafeRune)
+	if i < 0 {
+		return buf
+	}
+	tail := append([]byte(nil), buf[start+i:]...)
+	buf = buf[:start+i]
+	for len(tail) > 0 {
+		r, size := utf8.DecodeRune(tail)
+		if isUnsafeRune(r) {
+			q := strconv.QuoteRune(r)
+			buf = append(buf, q[1:len(q)-1]...)
+		} else {
+			buf = append(buf, tail[:size]...)
+		}
+		tail = tail[size:]
+	}
+	return buf
+}
+
+// isUnsafeRune reports whether r is a control character or line separator which must not be printed verbatim.
+func isUnsafeRune(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
+
+// trimEOL removes single trailing line break from s.
+func trimEOL(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		s = s[:len(s)-1]
+		return strings.TrimSuffix(s, "\r")
+	}
+	return s
+}
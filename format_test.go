@@ -0,0 +1,62 @@
+package twigsnake
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetFormatterConcurrent(t *testing.T) {
+	const writers, messages = 4, 200
+	l, buf := newTestLogger(t, LOG_DEBUG)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < writers*messages; i++ {
+			l.Named("switch").SetFormat([]Format{FormatText, FormatJSON}[i%2])
+			runtime.Gosched()
+		}
+	}()
+	for g := 0; g < writers; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < messages; i++ {
+				l.Infow("message", "g", g, "i", i)
+				runtime.Gosched()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	got := lines(buf)
+	if len(got) != writers*messages {
+		t.Fatalf("%d lines, want %d", len(got), writers*messages)
+	}
+	for _, line := range got {
+		if !strings.HasPrefix(line, "{") {
+			var g, i int
+			if n, err := fmt.Sscanf(line, "[INFO] message g=%d i=%d", &g, &i); n != 2 || err != nil {
+				t.Errorf("invalid text line %q", line)
+			} else if want := fmt.Sprintf("[INFO] message g=%d i=%d", g, i); line != want {
+				t.Errorf("text line %q, want %q", line, want)
+			}
+			continue
+		}
+		var v struct {
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+			G, I  *int
+		}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Errorf("invalid JSON line %q: %v", line, err)
+		} else if v.Level != "info" || v.Msg != "message" || v.G == nil || v.I == nil {
+			t.Errorf("incomplete JSON line %q", line)
+		}
+	}
+}
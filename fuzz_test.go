@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+// lineBreaks are the characters which may split a line for log readers and terminals.
+const lineBreaks = "\n\r\v\f\u0085\u2028\u2029"
+
+func FuzzQuoteLogfmt(f *testing.F) {
+	for _, s := range []string{"", " ", " lead", "trail ", `say "hi"`, `"`, `\`, "a=b", "line\nbreak", "\r\n", "\xff",
+		"ok\xc3", "\u2028", "\x1b[31m", "plain"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		q := quoteLogfmt(s)
+		if strings.ContainsAny(q, lineBreaks) {
+			t.Fatalf("quoteLogfmt(%q) = %q spans lines", s, q)
+		}
+		pairs, err := parseLogfmt("k=" + q + " next=1")
+		if err != nil {
+			t.Fatalf("quoteLogfmt(%q) = %q doesn't parse: %v", s, q, err)
+		}
+		if len(pairs) != 2 || pairs[0] != [2]string{"k", s} || pairs[1] != [2]string{"next", "1"} {
+			t.Fatalf("quoteLogfmt(%q) = %q parses as %q", s, q, pairs)
+		}
+	})
+}
+
+func FuzzSanitizePrefix(f *testing.F) {
+	for _, s := range []string{"", "[app] ", "a\nb", "fake\n[ERROR] forged ", "\r", "\x1b[2J", "\u2028", "\u0085",
+		"\xff\n"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, prefix string) {
+		if p := sanitizePrefix(prefix); strings.ContainsAny(p, lineBreaks) {
+			t.Fatalf("sanitizePrefix(%q) = %q spans lines", prefix, p)
+		}
+
+		l, buf := newTestLogger(t, LOG_INFO)
+		l.InfoLogger.SetPrefix(prefix)
+		l.Info("m")
+		if out := strings.TrimSuffix(buf.String(), "\n"); strings.ContainsAny(out, lineBreaks) {
+			t.Fatalf("prefix %q split line %q", prefix, out)
+		}
+	})
+}
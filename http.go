@@ -0,0 +1,115 @@
+package twigsnake
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// SetRequestHeaders sets request headers which Request and Middleware log in addition to the standard fields, each
+// as "header.Name" field. No headers are logged by default, so sensitive ones such as Authorization or Cookie never
+// end up in logs unless listed explicitly.
+func (l *Logger) SetRequestHeaders(names ...string) {
+	canonical := make([]string, len(names))
+	for i, name := range names {
+		canonical[i] = http.CanonicalHeaderKey(name)
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reqHeaders = canonical
+}
+
+// Request prints access log entry for HTTP request r on given level: "METHOD path" message with status, duration,
+// remote_addr and user_agent fields, followed by headers chosen with SetRequestHeaders. Nothing is done if the level is
+// disabled.
+func (l *Logger) Request(level int, r *http.Request, status int, dur time.Duration) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	b := l.base()
+	b.mu.Lock()
+	headers := b.reqHeaders
+	b.mu.Unlock()
+
+	fields := make([]Field, 0, 4+len(headers))
+	fields = append(fields,
+		Field{"status", status},
+		Field{"duration", dur},
+		Field{"remote_addr", r.RemoteAddr},
+		Field{"user_agent", r.UserAgent()},
+	)
+	for _, h := range headers {
+		if v, ok := r.Header[h]; ok {
+			fields = append(fields, Field{"header." + h, v})
+		}
+	}
+	l.output(level, r.Method+" "+r.URL.Path, fields)
+}
+
+// statusRecorder is http.ResponseWriter remembering response status. It forwards http.Flusher and http.Hijacker to the
+// original ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher, so that streaming handlers work behind Middleware. It does nothing if the original
+// ResponseWriter can't flush.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, so that e.g. WebSocket handlers work behind Middleware. It fails if the original
+// ResponseWriter doesn't support hijacking.
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("twigsnake: ResponseWriter doesn't support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the original ResponseWriter, so that http.ResponseController can reach its optional features.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Middleware returns handler calling next and printing access log entry for every request on given level, as Request
+// does. Duration is measured with the logger's clock.
+func (l *Logger) Middleware(level int, next http.Handler) http.Handler {
+	now := l.base().now
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		l.Request(level, r, rec.status, now().Sub(start))
+	})
+}
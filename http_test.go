@@ -0,0 +1,105 @@
+package twigsnake
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequest(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	l.SetRequestHeaders("x-request-id", "Authorization")
+	r := httptest.NewRequest("GET", "/users?id=1", nil)
+	r.Header.Set("User-Agent", "test")
+	r.Header.Set("X-Request-Id", "abc")
+	l.Request(LOG_INFO, r, http.StatusCreated, 0)
+	want := "[INFO] GET /users status=201 duration=0s remote_addr=192.0.2.1:1234 user_agent=test header.X-Request-Id=[abc]"
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  string
+	}{
+		{"nothing written", func(w http.ResponseWriter, r *http.Request) {}, "status=200"},
+		{"body only", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) }, "status=200"},
+		{"explicit status", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			"status=404"},
+		{"first status wins", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.WriteHeader(http.StatusOK)
+		}, "status=418"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			rec := httptest.NewRecorder()
+			l.Middleware(LOG_INFO, tt.handler).ServeHTTP(rec, httptest.NewRequest("POST", "/x", nil))
+			if got := buf.String(); !strings.HasPrefix(got, "[INFO] POST /x "+tt.status+" duration=0s ") {
+				t.Errorf("output %q, want entry with %s", got, tt.status)
+			}
+		})
+	}
+}
+
+func TestMiddlewareFlush(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	rec := httptest.NewRecorder()
+	l.Middleware(LOG_INFO, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("ResponseWriter doesn't implement http.Flusher")
+		}
+		f.Flush()
+	})).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !rec.Flushed {
+		t.Error("Flush not forwarded to the original ResponseWriter")
+	}
+}
+
+func TestMiddlewareHijack(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	handler := l.Middleware(LOG_INFO, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("ResponseWriter doesn't implement http.Hijacker")
+			return
+		}
+		conn, rw, err := h.Hijack()
+		if err != nil {
+			t.Errorf("Hijack failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n\r\nhello\n")
+		_ = rw.Flush()
+	}))
+
+	t.Run("server", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+		conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: x\r\n\r\n"))
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("response %v, %v; want 101", resp, err)
+		}
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		rec := &statusRecorder{ResponseWriter: httptest.NewRecorder()}
+		if _, _, err := rec.Hijack(); err == nil {
+			t.Error("Hijack of ResponseWriter without hijacking support succeeded")
+		}
+	})
+}
@@ -0,0 +1,350 @@
+package twigsnake
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Default names of the core keys of structured formats.
+const (
+	DefaultTimeKey    = "time"
+	DefaultLevelKey   = "level"
+	DefaultMessageKey = "msg"
+)
+
+// sourceKey is the key of call site file and line in structured formats. It differs from "caller" field added by
+// SetCallerFunc, so both can be reported at once.
+const sourceKey = "source"
+
+// jsonFormatter renders entries as JSON objects, one per line.
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(buf []byte, e *Entry) []byte {
+	buf = append(buf, '{')
+	buf = appendJSONString(buf, keyOrDefault(e.TimeKey, DefaultTimeKey))
+	buf = append(buf, ':')
+	buf = appendJSONString(buf, e.Time.Format(time.RFC3339))
+	buf = append(buf, ',')
+	buf = appendJSONString(buf, keyOrDefault(e.LevelKey, DefaultLevelKey))
+	buf = append(buf, ':')
+	buf = appendJSONString(buf, e.LevelName())
+	buf = append(buf, ',')
+	buf = appendJSONString(buf, keyOrDefault(e.MessageKey, DefaultMessageKey))
+	buf = append(buf, ':')
+	buf = appendJSONString(buf, e.Message)
+	if e.File != "" {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, sourceKey)
+		buf = append(buf, ':')
+		buf = appendJSONString(buf, e.File+":"+strconv.Itoa(e.Line))
+	}
+	for _, f := range e.Fields {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, f.Key)
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, f.Value, e.MaxDepth, e.BytesEncoding)
+	}
+	return append(buf, '}')
+}
+
+// cloudWatchFormatter renders entries as JSON objects understood by Amazon CloudWatch Logs Insights out of the box: level
+// name, message under "message" key and time as "timestamp" in milliseconds since Unix epoch, followed by fields. Keys
+// set with SetTimeKey and friends don't apply.
+type cloudWatchFormatter struct{}
+
+func (cloudWatchFormatter) Format(buf []byte, e *Entry) []byte {
+	buf = append(buf, `{"level":`...)
+	buf = appendJSONString(buf, e.LevelName())
+	buf = append(buf, `,"message":`...)
+	buf = appendJSONString(buf, e.Message)
+	buf = append(buf, `,"timestamp":`...)
+	buf = strconv.AppendInt(buf, e.Time.UnixNano()/int64(time.Millisecond), 10)
+	for _, f := range e.Fields {
+		buf = append(buf, ',')
+		buf = appendJSONString(buf, f.Key)
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, f.Value, e.MaxDepth, e.BytesEncoding)
+	}
+	return append(buf, '}')
+}
+
+// keyOrDefault returns key, or def if key is empty.
+func keyOrDefault(key, def string) string {
+	if key == "" {
+		return def
+	}
+	return key
+}
+
+// SetTimeKey sets the key under which structured formats such as FormatJSON put message time, e.g. "@timestamp" as
+// Elastic Common Schema requires. Empty key restores DefaultTimeKey.
+func (l *Logger) SetTimeKey(key string) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeKey = key
+}
+
+// SetLevelKey sets the key under which structured formats such as FormatJSON put level name, e.g. "log.level" as
+// Elastic Common Schema requires. Empty key restores DefaultLevelKey.
+func (l *Logger) SetLevelKey(key string) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levelKey = key
+}
+
+// SetMessageKey sets the key under which structured formats such as FormatJSON put message text, e.g. "message".
+// Empty key restores DefaultMessageKey.
+func (l *Logger) SetMessageKey(key string) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messageKey = key
+}
+
+// appendJSONValue appends structured field value v to buf as JSON: nil values (including nil pointers, errors, maps and
+// slices) as null, booleans, numbers and strings natively, slices and arrays as JSON arrays up to maxDepth levels deep,
+// pointers as their pointees, everything else as a string holding its textual representation.
+func appendJSONValue(buf []byte, v interface{}, maxDepth int, enc BytesEncoding) []byte {
+	r := jsonRenderer{buf: buf, maxDepth: maxDepth, bytesEnc: enc}
+	r.render(v, 0)
+	return r.buf
+}
+
+// jsonRenderer renders values as JSON with bounded nesting depth and protection against reference cycles.
+type jsonRenderer struct {
+	buf      []byte
+	maxDepth int
+	bytesEnc BytesEncoding
+	visiting map[uintptr]bool
+}
+
+func (r *jsonRenderer) render(v interface{}, depth int) {
+	switch x := v.(type) {
+	case nil:
+		r.buf = append(r.buf, "null"...)
+		return
+	case time.Time:
+		r.buf = appendJSONString(r.buf, x.Format(time.RFC3339Nano))
+		return
+	case string:
+		r.buf = appendJSONString(r.buf, x)
+		return
+	case Stack:
+		r.renderStack(x)
+		return
+	case []byte:
+		if x == nil {
+			r.buf = append(r.buf, "null"...)
+		} else {
+			r.buf = append(r.buf, '"')
+			r.buf = appendBytes(r.buf, x, r.bytesEnc)
+			r.buf = append(r.buf, '"')
+		}
+		return
+	case bool:
+		r.buf = strconv.AppendBool(r.buf, x)
+		return
+	case int:
+		r.buf = strconv.AppendInt(r.buf, int64(x), 10)
+		return
+	case int8:
+		r.buf = strconv.AppendInt(r.buf, int64(x), 10)
+		return
+	case int16:
+		r.buf = strconv.AppendInt(r.buf, int64(x), 10)
+		return
+	case int32:
+		r.buf = strconv.AppendInt(r.buf, int64(x), 10)
+		return
+	case int64:
+		r.buf = strconv.AppendInt(r.buf, x, 10)
+		return
+	case uint:
+		r.buf = strconv.AppendUint(r.buf, uint64(x), 10)
+		return
+	case uint8:
+		r.buf = strconv.AppendUint(r.buf, uint64(x), 10)
+		return
+	case uint16:
+		r.buf = strconv.AppendUint(r.buf, uint64(x), 10)
+		return
+	case uint32:
+		r.buf = strconv.AppendUint(r.buf, uint64(x), 10)
+		return
+	case uint64:
+		r.buf = strconv.AppendUint(r.buf, x, 10)
+		return
+	case float32:
+		r.buf = appendJSONFloat(r.buf, float64(x), 32)
+		return
+	case float64:
+		r.buf = appendJSONFloat(r.buf, x, 64)
+		return
+	}
+
+	rv := reflect.ValueOf(v)
+	if isNilValue(rv) {
+		r.buf = append(r.buf, "null"...)
+		return
+	}
+	switch v.(type) {
+	case error, fmt.Stringer:
+		r.buf = appendJSONString(r.buf, string(appendValue(nil, v, r.maxDepth-depth, r.bytesEnc)))
+		return
+	}
+
+	switch k := rv.Kind(); {
+	case k == reflect.Ptr && rv.Elem().Kind() != reflect.Struct && rv.Elem().Kind() != reflect.Map:
+		r.renderPointer(rv, depth)
+	case (k == reflect.Slice || k == reflect.Array) && rv.Type().Elem().Kind() != reflect.Uint8:
+		r.renderArray(rv, depth)
+	default:
+		r.buf = appendJSONString(r.buf, string(appendValue(nil, v, r.maxDepth-depth, r.bytesEnc)))
+	}
+}
+
+// renderPointer renders pointee of non-nil pointer v. Pointers to structs and maps, which are rendered as strings, are
+// left to appendValue, so they get the same representation as in text formats.
+func (r *jsonRenderer) renderPointer(v reflect.Value, depth int) {
+	ptr := v.Pointer()
+	if r.visiting[ptr] {
+		r.buf = appendJSONString(r.buf, cycleMarker)
+		return
+	}
+	if depth >= r.maxDepth {
+		r.buf = appendJSONString(r.buf, maxDepthMarker)
+		return
+	}
+	if r.visiting == nil {
+		r.visiting = make(map[uintptr]bool)
+	}
+	r.visiting[ptr] = true
+	defer delete(r.visiting, ptr)
+	r.render(v.Elem().Interface(), depth+1)
+}
+
+// renderArray renders slice or array v as JSON array.
+func (r *jsonRenderer) renderArray(v reflect.Value, depth int) {
+	if v.Kind() == reflect.Slice {
+		if v.IsNil() {
+			r.buf = append(r.buf, "null"...)
+			return
+		}
+		if v.Len() > 0 {
+			ptr := v.Pointer()
+			if r.visiting[ptr] {
+				r.buf = appendJSONString(r.buf, cycleMarker)
+				return
+			}
+			if r.visiting == nil {
+				r.visiting = make(map[uintptr]bool)
+			}
+			r.visiting[ptr] = true
+			defer delete(r.visiting, ptr)
+		}
+	}
+	if depth >= r.maxDepth {
+		r.buf = appendJSONString(r.buf, maxDepthMarker)
+		return
+	}
+
+	r.buf = append(r.buf, '[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		r.render(v.Index(i).Interface(), depth+1)
+	}
+	r.buf = append(r.buf, ']')
+}
+
+// renderStack renders stack s as JSON array of {"func","file","line"} objects, innermost frame first.
+func (r *jsonRenderer) renderStack(s Stack) {
+	if s == nil {
+		r.buf = append(r.buf, "null"...)
+		return
+	}
+	r.buf = append(r.buf, '[')
+	for i, f := range s {
+		if i > 0 {
+			r.buf = append(r.buf, ',')
+		}
+		r.buf = append(r.buf, `{"func":`...)
+		r.buf = appendJSONString(r.buf, f.Func)
+		r.buf = append(r.buf, `,"file":`...)
+		r.buf = appendJSONString(r.buf, f.File)
+		r.buf = append(r.buf, `,"line":`...)
+		r.buf = strconv.AppendInt(r.buf, int64(f.Line), 10)
+		r.buf = append(r.buf, '}')
+	}
+	r.buf = append(r.buf, ']')
+}
+
+// appendJSONFloat appends f to buf as JSON number. NaN and infinities, which JSON can't represent, are rendered as
+// strings.
+func appendJSONFloat(buf []byte, f float64, bitSize int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendJSONString(buf, strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, bitSize)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s to buf as quoted JSON string. Control characters, quotes and backslashes are escaped;
+// invalid UTF-8 is replaced with U+FFFD, so the result is always valid JSON.
+func appendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are valid in JSON but break JavaScript parsers, escape them like encoding/json does.
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, `\u202`...)
+			buf = append(buf, hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
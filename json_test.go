@@ -0,0 +1,77 @@
+package twigsnake
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormat(t *testing.T) {
+	ts := testTime.Format(time.RFC3339)
+	tests := []struct {
+		name  string
+		setup func(l *Logger)
+		log   func(l *Logger)
+		want  map[string]interface{}
+	}{
+		{"Print", func(l *Logger) {}, func(l *Logger) { l.Error("disk ", 3, " failed") },
+			map[string]interface{}{"time": ts, "level": "error", "msg": "disk 3 failed"}},
+		{"Printf", func(l *Logger) {}, func(l *Logger) { l.Warnf("%d%%", 90) },
+			map[string]interface{}{"time": ts, "level": "warn", "msg": "90%"}},
+		{"Println", func(l *Logger) {}, func(l *Logger) { l.Debugln("a", 1) },
+			map[string]interface{}{"time": ts, "level": "debug", "msg": "a 1"}},
+		{"escaped message", func(l *Logger) {}, func(l *Logger) { l.Info("say \"hi\"\n\tbye\x00") },
+			map[string]interface{}{"time": ts, "level": "info", "msg": "say \"hi\"\n\tbye\x00"}},
+		{"fields", func(l *Logger) {}, func(l *Logger) { l.Named("db").Noticew("m", "n", 1, "ok", true, "s", []int{1}) },
+			map[string]interface{}{"time": ts, "level": "notice", "msg": "m", "logger": "db", "n": 1.0, "ok": true,
+				"s": []interface{}{1.0}}},
+		{"custom keys", func(l *Logger) {
+			l.SetTimeKey("@timestamp")
+			l.SetLevelKey("severity")
+			l.SetMessageKey("message")
+		}, func(l *Logger) { l.Crit("m") },
+			map[string]interface{}{"@timestamp": ts, "severity": "crit", "message": "m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Prefixes and flags of the underlying loggers must not leak into JSON.
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatJSON), WithPrefixes(map[int]string{LOG_INFO: "I "}))
+			tt.setup(l)
+			tt.log(l)
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+			if n := len(lines(buf)); n != 1 {
+				t.Errorf("%d lines written, want 1", n)
+			}
+		})
+	}
+}
+
+func TestCloudWatchFormat(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatCloudWatch))
+	l.SetTimeKey("@timestamp") // custom keys don't apply
+	l.SetMessageKey("msg")
+	l.Error("disk \"sda\"\nfailed")
+	l.Named("db").Infow("slow", "ms", 1500, "ok", false)
+
+	want := []string{
+		`{"level":"error","message":"disk \"sda\"\nfailed","timestamp":1614954615123}`,
+		`{"level":"info","message":"slow","timestamp":1614954615123,"logger":"db","ms":1500,"ok":false}`,
+	}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, line := range lines(buf) {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("invalid JSON %q", line)
+		}
+	}
+}
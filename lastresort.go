@@ -0,0 +1,43 @@
+package twigsnake
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// lastResortInterval is the minimal interval between diagnostic messages sent to the last resort writer.
+const lastResortInterval = time.Minute
+
+// SetLastResort sets the writer receiving diagnostic line when writing a message to its output fails, so that broken
+// logging doesn't go unnoticed. Diagnostics are rate-limited to one per minute, the next one reporting how many
+// failures were suppressed in between. By default os.Stderr is used; nil writer disables diagnostics.
+func (l *Logger) SetLastResort(w io.Writer) {
+	r := l.base()
+	r.lastResortMu.Lock()
+	defer r.lastResortMu.Unlock()
+	r.lastResort = w
+}
+
+// writeFailed reports failed write to the last resort writer.
+func (l *Logger) writeFailed(err error) {
+	l.lastResortMu.Lock()
+	defer l.lastResortMu.Unlock()
+	if l.lastResort == nil {
+		return
+	}
+
+	now := l.now()
+	if !l.lastResortAt.IsZero() && now.Sub(l.lastResortAt) < lastResortInterval {
+		l.lastResortSuppressed++
+		return
+	}
+
+	msg := "twigsnake: failed to write log message: " + err.Error()
+	if l.lastResortSuppressed > 0 {
+		msg += fmt.Sprintf(" (%d more failures suppressed)", l.lastResortSuppressed)
+	}
+	fmt.Fprintln(l.lastResort, msg)
+	l.lastResortAt = now
+	l.lastResortSuppressed = 0
+}
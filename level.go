@@ -0,0 +1,57 @@
+package twigsnake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// levelNames holds canonical textual names of severity levels indexed by level value.
+var levelNames = [8]string{"emerg", "alert", "crit", "error", "warn", "notice", "info", "debug"}
+
+// levelAliases maps alternative spellings (full RFC 5424 names and common abbreviations) to severity levels.
+var levelAliases = map[string]int{
+	"emergency":     LOG_EMERG,
+	"critical":      LOG_CRIT,
+	"err":           LOG_ERROR,
+	"warning":       LOG_WARN,
+	"informational": LOG_INFO,
+}
+
+// ParseLevel converts textual severity name into one of LOG_* constants. Names are case-insensitive and surrounding
+// whitespace is ignored. Besides canonical names (emerg, alert, crit, error, warn, notice, info and debug) full RFC 5424
+// names like "emergency", "critical", "warning" and "informational" are accepted as well.
+func ParseLevel(s string) (int, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for lvl, n := range levelNames {
+		if n == name {
+			return lvl, nil
+		}
+	}
+	if lvl, ok := levelAliases[name]; ok {
+		return lvl, nil
+	}
+	return 0, fmt.Errorf("unknown severity level %q", s)
+}
+
+// LevelName returns canonical textual name of severity level, the one accepted by ParseLevel. Error is returned for
+// values outside of LOG_EMERG..LOG_DEBUG range.
+func LevelName(level int) (string, error) {
+	if err := checkLogLevel(level); err != nil {
+		return "", err
+	}
+	return levelNames[level], nil
+}
+
+// LevelFromString is an alias of ParseLevel, named to pair with LevelString.
+func LevelFromString(s string) (int, error) {
+	return ParseLevel(s)
+}
+
+// LevelString returns canonical textual name of severity level, like LevelName does, but never fails: values outside
+// of LOG_EMERG..LOG_DEBUG range are rendered as "level(N)". It is handy in messages and fmt verbs.
+func LevelString(lvl int) string {
+	if checkLogLevel(lvl) != nil {
+		return fmt.Sprintf("level(%d)", lvl)
+	}
+	return levelNames[lvl]
+}
@@ -0,0 +1,93 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLevelFromString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"emerg", LOG_EMERG},
+		{"alert", LOG_ALERT},
+		{"crit", LOG_CRIT},
+		{"error", LOG_ERROR},
+		{"warn", LOG_WARN},
+		{"notice", LOG_NOTICE},
+		{"info", LOG_INFO},
+		{"debug", LOG_DEBUG},
+		{"WARN", LOG_WARN},
+		{"DeBuG", LOG_DEBUG},
+		{" \tinfo\n", LOG_INFO},
+		{"Warning", LOG_WARN},
+		{"critical", LOG_CRIT},
+		{"emergency", LOG_EMERG},
+		{"err", LOG_ERROR},
+		{"informational", LOG_INFO},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := LevelFromString(tt.in)
+			if err != nil || got != tt.want {
+				t.Errorf("LevelFromString(%q) = %d, %v; want %d", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelFromStringInvalid(t *testing.T) {
+	for _, in := range []string{"", " ", "verbose", "inf", "debug2", "6", "in fo"} {
+		_, err := LevelFromString(in)
+		if err == nil {
+			t.Errorf("LevelFromString(%q) succeeded", in)
+		} else if !strings.Contains(err.Error(), "unknown severity level") {
+			t.Errorf("LevelFromString(%q) error %q isn't descriptive", in, err)
+		}
+	}
+}
+
+func TestLevelString(t *testing.T) {
+	tests := []struct {
+		lvl  int
+		want string
+	}{
+		{LOG_EMERG, "emerg"},
+		{LOG_ERROR, "error"},
+		{LOG_WARN, "warn"},
+		{LOG_DEBUG, "debug"},
+		{-1, "level(-1)"},
+		{8, "level(8)"},
+	}
+	for _, tt := range tests {
+		if got := LevelString(tt.lvl); got != tt.want {
+			t.Errorf("LevelString(%d) = %q, want %q", tt.lvl, got, tt.want)
+		}
+	}
+	for lvl := LOG_EMERG; lvl <= LOG_DEBUG; lvl++ {
+		if got, err := LevelFromString(LevelString(lvl)); err != nil || got != lvl {
+			t.Errorf("LevelFromString(LevelString(%d)) = %d, %v", lvl, got, err)
+		}
+	}
+}
+
+func TestLevelNameRoundTrip(t *testing.T) {
+	for lvl := LOG_EMERG; lvl <= LOG_DEBUG; lvl++ {
+		name, err := LevelName(lvl)
+		if err != nil {
+			t.Fatalf("LevelName(%d): %v", lvl, err)
+		}
+		if name != strings.ToLower(strings.TrimSpace(name)) {
+			t.Errorf("LevelName(%d) = %q isn't canonical", lvl, name)
+		}
+		if got, err := ParseLevel(name); err != nil || got != lvl {
+			t.Errorf("ParseLevel(LevelName(%d)) = %d, %v", lvl, got, err)
+		}
+	}
+	for _, lvl := range []int{-1, LOG_DEBUG + 1, 100} {
+		if name, err := LevelName(lvl); err == nil {
+			t.Errorf("LevelName(%d) = %q, want error", lvl, name)
+		}
+	}
+}
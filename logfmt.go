@@ -0,0 +1,79 @@
+package twigsnake
+
+import (
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// logfmtFormatter renders entries as logfmt lines: time, level and msg pairs followed by source, if flags ask for it,
+// and fields in order of appearance. Values are quoted when necessary (see quoteLogfmt), so embedded quotes, spaces and
+// line breaks are escaped and every entry stays on a single line. Keys can't be quoted in logfmt, so characters which
+// would break the pair apart are replaced (see appendLogfmtKey). Core keys can be changed with SetTimeKey and friends.
+type logfmtFormatter struct{}
+
+func (logfmtFormatter) Format(buf []byte, e *Entry) []byte {
+	buf = appendLogfmtKey(buf, keyOrDefault(e.TimeKey, DefaultTimeKey))
+	buf = append(buf, '=')
+	buf = e.Time.AppendFormat(buf, time.RFC3339)
+	buf = append(buf, ' ')
+	buf = appendLogfmtKey(buf, keyOrDefault(e.LevelKey, DefaultLevelKey))
+	buf = append(buf, '=')
+	buf = append(buf, e.LevelName()...)
+	buf = append(buf, ' ')
+	buf = appendLogfmtKey(buf, keyOrDefault(e.MessageKey, DefaultMessageKey))
+	buf = append(buf, '=')
+	buf = append(buf, quoteLogfmt(e.Message)...)
+	if e.File != "" {
+		buf = append(buf, ' ')
+		buf = append(buf, sourceKey...)
+		buf = append(buf, '=')
+		buf = append(buf, quoteLogfmt(e.File+":"+strconv.Itoa(e.Line))...)
+	}
+	for _, f := range e.Fields {
+		buf = append(buf, ' ')
+		buf = appendLogfmtKey(buf, f.Key)
+		buf = append(buf, '=')
+		buf = append(buf, quoteLogfmt(string(appendValue(nil, f.Value, e.MaxDepth, e.BytesEncoding)))...)
+	}
+	return buf
+}
+
+// appendLogfmtKey appends key to buf with every character which must be quoted in logfmt value (see quoteLogfmt)
+// replaced with '_', so that the key can't split the pair or forge other pairs. Empty key becomes "_".
+func appendLogfmtKey(buf []byte, key string) []byte {
+	if key == "" {
+		return append(buf, '_')
+	}
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) || r == utf8.RuneError && size == 1 {
+			buf = append(buf, '_')
+		} else {
+			buf = append(buf, key[i:i+size]...)
+		}
+		i += size
+	}
+	return buf
+}
+
+// quoteLogfmt returns s in the form suitable for logfmt value: as is when it is a plain token, otherwise quoted with
+// Go escaping rules (see strconv.Quote). Values are quoted if they are empty or contain spaces, '=', '"', control or
+// non-printable characters, or invalid UTF-8, so the original value can always be recovered with strconv.Unquote.
+func quoteLogfmt(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size == 1 {
+				return strconv.Quote(s)
+			}
+		}
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package twigsnake
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// parseLogfmt splits logfmt line into key/value pairs, unquoting quoted values.
+func parseLogfmt(line string) ([][2]string, error) {
+	var pairs [][2]string
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsAny(line[:eq], " \"") {
+			return nil, errors.New("malformed key in " + strconv.Quote(line))
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, errors.New("unterminated value in " + strconv.Quote(line))
+			}
+			var err error
+			if value, err = strconv.Unquote(line[:end+1]); err != nil {
+				return nil, err
+			}
+			line = line[end+1:]
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			value, line = line[:end], line[end:]
+		}
+		pairs = append(pairs, [2]string{key, value})
+
+		if line != "" {
+			if line[0] != ' ' {
+				return nil, errors.New("missing separator before " + strconv.Quote(line))
+			}
+			line = line[1:]
+		}
+	}
+	return pairs, nil
+}
+
+func TestQuoteLogfmt(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"", `""`},
+		{"a b", `"a b"`},
+		{" padded ", `" padded "`},
+		{"a=b", `"a=b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"two\nlines", `"two\nlines"`},
+		{"tab\t", `"tab\t"`},
+		{"żółw", "żółw"},
+		{"bad\xffutf8", `"bad\xffutf8"`},
+		{"\u2028", `"\u2028"`},
+	}
+	for _, tt := range tests {
+		got := quoteLogfmt(tt.in)
+		if got != tt.want {
+			t.Errorf("quoteLogfmt(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+		if back, err := strconv.Unquote(got); got != tt.in && (err != nil || back != tt.in) {
+			t.Errorf("quoteLogfmt(%q) = %s doesn't unquote back: %q, %v", tt.in, got, back, err)
+		}
+	}
+}
+
+func TestLogfmtFormat(t *testing.T) {
+	core := [][2]string{{"time", "2021-03-05T14:30:15Z"}, {"level", "info"}}
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+		want [][2]string
+	}{
+		{"message needing quotes", func(l *Logger) { l.Info(`say "hi"` + "\nbye") },
+			[][2]string{{"msg", "say \"hi\"\nbye"}}},
+		{"fields in insertion order", func(l *Logger) { l.With("z", 1).With("a", "b c").Infow("m", "k", "") },
+			[][2]string{{"msg", "m"}, {"z", "1"}, {"a", "b c"}, {"k", ""}}},
+		{"forged pairs in key", func(l *Logger) { l.Infow("m", "a b=c\nlevel=emerg msg=forged", 1) },
+			[][2]string{{"msg", "m"}, {"a_b_c_level_emerg_msg_forged", "1"}}},
+		{"quote in key", func(l *Logger) { l.Infow("m", `k"`, 1) }, [][2]string{{"msg", "m"}, {"k_", "1"}}},
+		{"empty key", func(l *Logger) { l.Infow("m", "", 1) }, [][2]string{{"msg", "m"}, {"_", "1"}}},
+		{"invalid UTF-8 in key", func(l *Logger) { l.Infow("m", "k\xff", 1) }, [][2]string{{"msg", "m"}, {"k_", "1"}}},
+		{"non-ASCII key", func(l *Logger) { l.Infow("m", "żółw", 1) }, [][2]string{{"msg", "m"}, {"żółw", "1"}}},
+		{"custom message key", func(l *Logger) { l.SetMessageKey("the msg"); l.Info("m") },
+			[][2]string{{"the_msg", "m"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatLogfmt))
+			tt.log(l)
+			got := lines(buf)
+			if len(got) != 1 {
+				t.Fatalf("printed %d lines, want 1: %q", len(got), got)
+			}
+			pairs, err := parseLogfmt(got[0])
+			if err != nil {
+				t.Fatalf("unparsable line %q: %v", got[0], err)
+			}
+			want := append(core[:len(core):len(core)], tt.want...)
+			if len(pairs) != len(want) {
+				t.Fatalf("pairs %q, want %q", pairs, want)
+			}
+			for i := range want {
+				if pairs[i] != want[i] {
+					t.Errorf("pair %d = %q, want %q", i, pairs[i], want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package twigsnake
+
+import "sync/atomic"
+
+// maskActive is set in Logger.levelMask while the mask is in use, so that a mask enabling LOG_EMERG only is non-zero.
+const maskActive = 1 << 8
+
+// SetEnabledLevels replaces "this level and everything more severe" logging threshold with explicit set of enabled
+// levels, which need not be contiguous: e.g. LOG_INFO and LOG_ERROR can be enabled while noisy LOG_WARN and LOG_NOTICE
+// are suppressed. Emergency messages are always enabled. Invalid levels are ignored. The mask applies to named loggers
+// too, overriding component levels, until it is dropped with SetLogLevel.
+func (l *Logger) SetEnabledLevels(levels ...int) {
+	mask := uint32(maskActive | 1<<LOG_EMERG)
+	for _, lvl := range levels {
+		if checkLogLevel(lvl) == nil {
+			mask |= 1 << uint(lvl)
+		}
+	}
+	r := l.base()
+	atomic.StoreUint32(&r.levelMask, mask)
+	atomic.AddUint64(&r.levelGen, 1)
+}
+
+// EnableLevel enables messages of given level. If no mask is in use yet, it is initialized with levels enabled by
+// current logging level (see SetEnabledLevels). Invalid level is ignored.
+func (l *Logger) EnableLevel(level int) {
+	if checkLogLevel(level) == nil {
+		l.updateMask(func(mask uint32) uint32 { return mask | 1<<uint(level) })
+	}
+}
+
+// DisableLevel disables messages of given level, except for LOG_EMERG, which is always enabled. If no mask is in use
+// yet, it is initialized with levels enabled by current logging level (see SetEnabledLevels). Invalid level is ignored.
+func (l *Logger) DisableLevel(level int) {
+	if checkLogLevel(level) == nil && level != LOG_EMERG {
+		l.updateMask(func(mask uint32) uint32 { return mask &^ (1 << uint(level)) })
+	}
+}
+
+// IsEnabled reports whether messages of given level are printed. It is the same as EffectiveEnabled.
+func (l *Logger) IsEnabled(level int) bool {
+	return l.EffectiveEnabled(level)
+}
+
+// updateMask atomically replaces mask of enabled levels with fn applied to it, initializing the mask from logging level
+// first if it is not in use.
+func (l *Logger) updateMask(fn func(mask uint32) uint32) {
+	r := l.base()
+	for {
+		old := atomic.LoadUint32(&r.levelMask)
+		mask := old
+		if mask == 0 {
+			mask = maskActive | (1<<uint(r.LogLevel()+1) - 1)
+		}
+		if atomic.CompareAndSwapUint32(&r.levelMask, old, fn(mask)) {
+			atomic.AddUint64(&r.levelGen, 1)
+			return
+		}
+	}
+}
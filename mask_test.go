@@ -0,0 +1,68 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnabledLevels(t *testing.T) {
+	all := []int{LOG_EMERG, LOG_ALERT, LOG_CRIT, LOG_ERROR, LOG_WARN, LOG_NOTICE, LOG_INFO, LOG_DEBUG}
+	tests := []struct {
+		name    string
+		level   int
+		setup   func(l *Logger)
+		enabled []int
+	}{
+		{"threshold", LOG_NOTICE, func(l *Logger) {}, all[:6]},
+		{"non-contiguous set", LOG_DEBUG, func(l *Logger) { l.SetEnabledLevels(LOG_INFO, LOG_ERROR) },
+			[]int{LOG_EMERG, LOG_ERROR, LOG_INFO}},
+		{"emerg always enabled", LOG_DEBUG, func(l *Logger) { l.SetEnabledLevels() }, []int{LOG_EMERG}},
+		{"invalid levels ignored", LOG_DEBUG, func(l *Logger) { l.SetEnabledLevels(-1, LOG_DEBUG, 8) },
+			[]int{LOG_EMERG, LOG_DEBUG}},
+		{"DisableLevel starts from threshold", LOG_INFO, func(l *Logger) { l.DisableLevel(LOG_WARN) },
+			[]int{LOG_EMERG, LOG_ALERT, LOG_CRIT, LOG_ERROR, LOG_NOTICE, LOG_INFO}},
+		{"EnableLevel starts from threshold", LOG_ERROR, func(l *Logger) { l.EnableLevel(LOG_DEBUG) },
+			[]int{LOG_EMERG, LOG_ALERT, LOG_CRIT, LOG_ERROR, LOG_DEBUG}},
+		{"EnableLevel and DisableLevel on mask", LOG_DEBUG, func(l *Logger) {
+			l.SetEnabledLevels(LOG_INFO)
+			l.EnableLevel(LOG_WARN)
+			l.DisableLevel(LOG_INFO)
+			l.DisableLevel(LOG_EMERG)
+		}, []int{LOG_EMERG, LOG_WARN}},
+		{"SetLogLevel drops mask", LOG_DEBUG, func(l *Logger) {
+			l.SetEnabledLevels(LOG_DEBUG)
+			l.SetLogLevel(LOG_WARN)
+		}, all[:5]},
+		{"mask overrides component levels", LOG_DEBUG, func(l *Logger) {
+			l.SetComponentLevel("db", LOG_EMERG)
+			l.Named("db").SetEnabledLevels(LOG_NOTICE)
+		}, []int{LOG_EMERG, LOG_NOTICE}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, tt.level)
+			tt.setup(l)
+			named := l.Named("db")
+			logs := []func(v ...interface{}){named.Emerg, named.Alert, named.Crit, named.Error, named.Warn, named.Notice,
+				named.Info, named.Debug}
+
+			var want []string
+			for _, lvl := range tt.enabled {
+				want = append(want, "["+strings.ToUpper(LevelString(lvl))+"] m logger=db")
+			}
+			for _, lvl := range all {
+				enabled := false
+				for _, e := range tt.enabled {
+					enabled = enabled || e == lvl
+				}
+				if got := named.IsEnabled(lvl); got != enabled {
+					t.Errorf("IsEnabled(%d) = %v, want %v", lvl, got, enabled)
+				}
+				logs[lvl]("m")
+			}
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("output %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package twigsnake
+
+import "runtime"
+
+// MemStats prints concise memory statistics on given level as "memstats" message with the following fields, so the
+// line is easy to grep for and is rendered as structured data by structured formats:
+//
+//	alloc       bytes of allocated heap objects (runtime.MemStats.HeapAlloc)
+//	sys         bytes of memory obtained from the OS (runtime.MemStats.Sys)
+//	num_gc      number of completed GC cycles (runtime.MemStats.NumGC)
+//	goroutines  number of existing goroutines (runtime.NumGoroutine)
+//
+// Reading memory statistics briefly stops the world, so nothing is done if the level is disabled.
+func (l *Logger) MemStats(level int) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	l.output(level, "memstats", []Field{
+		{"alloc", m.HeapAlloc},
+		{"sys", m.Sys},
+		{"num_gc", m.NumGC},
+		{"goroutines", runtime.NumGoroutine()},
+	})
+}
@@ -0,0 +1,40 @@
+package twigsnake
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMemStats(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO, WithFormat(FormatLogfmt))
+	l.MemStats(LOG_DEBUG)
+	if buf.Len() != 0 {
+		t.Fatalf("disabled level printed %q", buf.String())
+	}
+
+	runtime.GC()
+	l.MemStats(LOG_INFO)
+	pairs, err := parseLogfmt(strings.TrimSuffix(buf.String(), "\n"))
+	if err != nil {
+		t.Fatalf("%q: %v", buf.String(), err)
+	}
+	var keys []string
+	values := make(map[string]string)
+	for _, kv := range pairs {
+		keys = append(keys, kv[0])
+		values[kv[0]] = kv[1]
+	}
+	if values["level"] != "info" || values["msg"] != "memstats" {
+		t.Errorf("level=%s msg=%s, want info memstats", values["level"], values["msg"])
+	}
+	for _, key := range []string{"alloc", "sys", "num_gc", "goroutines"} {
+		if n, err := strconv.ParseUint(values[key], 10, 64); err != nil || n == 0 {
+			t.Errorf("%s=%s, want positive number", key, values[key])
+		}
+	}
+	if got, want := strings.Join(keys, " "), "time level msg alloc sys num_gc goroutines"; got != want {
+		t.Errorf("keys %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,12 @@
+package twigsnake
+
+// SetMeta attaches opaque metadata under key to the logger object, e.g. owning subsystem or creation time, for tools
+// inspecting loggers such as admin dashboards. Metadata is never printed. Named loggers have metadata of their own.
+func (l *Logger) SetMeta(key string, value interface{}) {
+	l.meta.Store(key, value)
+}
+
+// Meta returns metadata stored under key with SetMeta.
+func (l *Logger) Meta(key string) (interface{}, bool) {
+	return l.meta.Load(key)
+}
@@ -0,0 +1,35 @@
+package twigsnake
+
+import "testing"
+
+func TestMeta(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	db := l.Named("db")
+	l.SetMeta("owner", "payments")
+	l.SetMeta("owner", "billing") // replaces the previous value
+	db.SetMeta("pool", 4)
+
+	tests := []struct {
+		l     *Logger
+		key   string
+		value interface{}
+		ok    bool
+	}{
+		{l, "owner", "billing", true},
+		{l, "pool", nil, false},
+		{db, "pool", 4, true},
+		{db, "owner", nil, false}, // named loggers have metadata of their own
+	}
+	for _, tt := range tests {
+		if v, ok := tt.l.Meta(tt.key); v != tt.value || ok != tt.ok {
+			t.Errorf("Meta(%q) = %v, %v; want %v, %v", tt.key, v, ok, tt.value, tt.ok)
+		}
+	}
+
+	// Metadata is never printed.
+	l.Info("m")
+	db.Infow("m", "k", "v")
+	if got, want := buf.String(), "[INFO] m\n[INFO] m logger=db k=v\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package twigsnake
+
+import "io"
+
+// multiWriter duplicates writes to all its writers, isolating their failures from each other.
+type multiWriter struct {
+	writers []io.Writer
+}
+
+// MultiWriter creates writer duplicating every write to all given writers, like io.MultiWriter, but a failing writer
+// doesn't stop the rest: every writer receives every write, and the first error encountered, including short writes, is
+// returned after all of them were tried. The writer implements Flush and Close, which are passed on to writers
+// supporting them, so the logger's Flush and Close reach every destination; standard output and standard error are
+// never closed.
+func MultiWriter(writers ...io.Writer) io.Writer {
+	all := make([]io.Writer, 0, len(writers))
+	for _, w := range writers {
+		if mw, ok := w.(*multiWriter); ok {
+			all = append(all, mw.writers...)
+		} else if w != nil {
+			all = append(all, w)
+		}
+	}
+	return &multiWriter{all}
+}
+
+func (mw *multiWriter) Write(p []byte) (int, error) {
+	var first error
+	for _, w := range mw.writers {
+		n, err := w.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	if first != nil {
+		return 0, first
+	}
+	return len(p), nil
+}
+
+// Flush flushes every writer which implements Flush() error and returns the first error encountered.
+func (mw *multiWriter) Flush() error {
+	var first error
+	for _, w := range mw.writers {
+		if f, ok := w.(flusher); ok {
+			if err := f.Flush(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// Close closes every writer which implements io.Closer, except standard output and standard error, and returns the
+// first error encountered.
+func (mw *multiWriter) Close() error {
+	var first error
+	for _, w := range mw.writers {
+		if err := closeWriter(w); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// SetOutputFor sets output of the underlying logger of given level only, e.g. to send errors to both standard error
+// and a file with MultiWriter while other levels stay where they are. Error is returned if the level is invalid.
+func (l *Logger) SetOutputFor(level int, w io.Writer) error {
+	if err := checkLogLevel(level); err != nil {
+		return err
+	}
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loggers()[level].SetOutput(w)
+	return nil
+}
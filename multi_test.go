@@ -0,0 +1,111 @@
+package twigsnake
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestMultiWriter(t *testing.T) {
+	errFailed := errors.New("failed")
+	failing := funcWriter(func(p []byte) (int, error) { return 0, errFailed })
+	short := funcWriter(func(p []byte) (int, error) { return len(p) - 1, nil })
+
+	tests := []struct {
+		name    string
+		middle  io.Writer // writer placed between two buffers
+		wantErr error
+	}{
+		{"all succeed", &bytes.Buffer{}, nil},
+		{"failing writer", failing, errFailed},
+		{"short write", short, io.ErrShortWrite},
+		{"nil writer skipped", nil, nil},
+		{"nested", MultiWriter(failing, &bytes.Buffer{}), errFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, last bytes.Buffer
+			w := MultiWriter(&first, tt.middle, &last)
+			n, err := w.Write([]byte("line\n"))
+			if err != tt.wantErr {
+				t.Errorf("Write error %v, want %v", err, tt.wantErr)
+			}
+			wantN := 5
+			if tt.wantErr != nil {
+				wantN = 0
+			}
+			if n != wantN {
+				t.Errorf("Write = %d, want %d", n, wantN)
+			}
+			if first.String() != "line\n" || last.String() != "line\n" {
+				t.Errorf("writers received %q and %q", first.String(), last.String())
+			}
+		})
+	}
+}
+
+func TestMultiWriterLogger(t *testing.T) {
+	var a, b bytes.Buffer
+	failing := funcWriter(func(p []byte) (int, error) { return 0, errors.New("failed") })
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetOutput(MultiWriter(&a, failing, &b))
+	l.SetLastResort(nil)
+	l.Error("e")
+	l.Info("i")
+	for _, buf := range []*bytes.Buffer{&a, &b} {
+		if got := buf.String(); got != "[ERROR] e\n[INFO] i\n" {
+			t.Errorf("output %q", got)
+		}
+	}
+}
+
+func TestMultiWriterFinalizers(t *testing.T) {
+	a, b := &finalizer{}, &finalizer{}
+	w := MultiWriter(a, MultiWriter(b, &bytes.Buffer{}))
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetOutput(w)
+	if err := l.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for i, f := range []*finalizer{a, b} {
+		if f.flushes == 0 || f.closes != 1 {
+			t.Errorf("writer %d: %d flushes, %d closes; want some flushes and 1 close", i, f.flushes, f.closes)
+		}
+	}
+}
+
+func TestSetOutputFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   int
+		wantErr bool
+	}{
+		{"emerg", LOG_EMERG, false},
+		{"debug", LOG_DEBUG, false},
+		{"below range", -1, true},
+		{"above range", LOG_DEBUG + 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, main := newTestLogger(t, LOG_DEBUG)
+			var routed bytes.Buffer
+			err := l.Named("x").SetOutputFor(tt.level, &routed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetOutputFor(%d) error %v, want error %v", tt.level, err, tt.wantErr)
+			}
+			for lvl, lg := range l.loggers() {
+				want := io.Writer(main)
+				if lvl == tt.level {
+					want = &routed
+				}
+				if lg.Writer() != want {
+					t.Errorf("level %d writes to %p, want %p", lvl, lg.Writer(), want)
+				}
+			}
+		})
+	}
+}
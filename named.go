@@ -0,0 +1,112 @@
+package twigsnake
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Named returns logger for component with given name, sharing outputs, format and all other settings with l. Messages
+// logged through it carry the component name in "logger" field. Names of nested named loggers are joined with dots,
+// e.g. l.Named("db").Named("pool") belongs to component "db.pool". Logging level of named logger is the one set for its
+// component with SetComponentLevel, or for the closest parent component (e.g. "db" for "db.pool"), falling back to the
+// level of l. Other settings changed through named logger apply to the logger Named was originally called on, and so to
+// all loggers derived from it.
+func (l *Logger) Named(name string) *Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return l.child(name, l.fields)
+}
+
+// With returns logger appending given structured context, alternating keys and values (see Infow), to every message it
+// logs, e.g. l.With("request_id", id).Info("done") prints "done request_id=...". Context accumulates: fields of chained
+// With calls are rendered in order of addition, before fields of the message itself. The returned logger shares
+// outputs, format, level and all other settings with l, but l itself is not affected by With. Like for named loggers,
+// settings changed through it apply to the logger Named or With was originally called on.
+func (l *Logger) With(keysAndValues ...interface{}) *Logger {
+	fields := fieldsFromPairs(keysAndValues)
+	if len(l.fields) > 0 {
+		fields = append(l.fields[:len(l.fields):len(l.fields)], fields...)
+	}
+	return l.child(l.name, fields)
+}
+
+// child returns logger of component name carrying given context fields, which shares everything else with l.
+func (l *Logger) child(name string, fields []Field) *Logger {
+	r := l.base()
+	return &Logger{
+		root:          r,
+		name:          name,
+		fields:        fields,
+		EmergLogger:   r.EmergLogger,
+		AlertLogger:   r.AlertLogger,
+		CritLogger:    r.CritLogger,
+		ErrorLogger:   r.ErrorLogger,
+		WarningLogger: r.WarningLogger,
+		NoticeLogger:  r.NoticeLogger,
+		InfoLogger:    r.InfoLogger,
+		DebugLogger:   r.DebugLogger,
+	}
+}
+
+// SetComponentLevel sets logging level of named loggers of component name and its subcomponents, so that e.g. "db"
+// component can log at LOG_DEBUG while everything else stays at LOG_INFO. Invalid level (e.g. -1) removes component's
+// own level, so it falls back to the parent component or the global level again.
+func (l *Logger) SetComponentLevel(name string, level int) {
+	r := l.base()
+	if checkLogLevel(level) != nil {
+		r.components.Delete(name)
+	} else {
+		r.components.Store(name, level)
+	}
+	atomic.AddUint64(&r.levelGen, 1)
+}
+
+// cachedLevel returns logging level of named logger l. Resolved level is cached along with the generation of level
+// settings it was resolved at, so as long as levels don't change it costs just two atomic loads, without any locking.
+func (l *Logger) cachedLevel() int {
+	gen := atomic.LoadUint64(&l.root.levelGen)
+	if c := atomic.LoadUint64(&l.levelCache); c>>8 == gen+1 {
+		return int(c & 0xFF)
+	}
+	lvl := l.root.componentLevel(l.name)
+	atomic.StoreUint64(&l.levelCache, (gen+1)<<8|uint64(lvl))
+	return lvl
+}
+
+// componentLevel returns logging level of component name: its own one if set, otherwise the level of the closest parent
+// component or the global level.
+func (l *Logger) componentLevel(name string) int {
+	for {
+		if lvl, ok := l.components.Load(name); ok {
+			return lvl.(int)
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return l.LogLevel()
+		}
+		name = name[:i]
+	}
+}
+
+// withName returns fields preceded by context of l: "logger" field holding component name for named loggers and fields
+// added with With. Fields are returned as is if there is no context.
+func (l *Logger) withName(fields []Field) []Field {
+	if l.name == "" && len(l.fields) == 0 {
+		return fields
+	}
+	all := make([]Field, 0, 1+len(l.fields)+len(fields))
+	if l.name != "" {
+		all = append(all, Field{"logger", l.name})
+	}
+	all = append(all, l.fields...)
+	return append(all, fields...)
+}
+
+// base returns the logger l belongs to, which holds all settings: root logger for named loggers, l itself otherwise.
+func (l *Logger) base() *Logger {
+	if l.root != nil {
+		return l.root
+	}
+	return l
+}
@@ -0,0 +1,228 @@
+package twigsnake
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWith(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+		want string
+	}{
+		{"fields before message fields", func(l *Logger) { l.With("a", 1).Infow("m", "b", 2) }, "[INFO] m a=1 b=2"},
+		{"chained in order of addition", func(l *Logger) { l.With("a", 1).With("b", 2).With("c", 3).Info("m") },
+			"[INFO] m a=1 b=2 c=3"},
+		{"siblings don't share fields", func(l *Logger) {
+			parent := l.With("a", 1)
+			parent.With("b", 2)
+			parent.With("c", 3).Info("m")
+		}, "[INFO] m a=1 c=3"},
+		{"parent unaffected", func(l *Logger) {
+			l.With("a", 1)
+			l.Info("m")
+		}, "[INFO] m"},
+		{"missing value", func(l *Logger) { l.With("a").Info("m") }, "[INFO] m a=!MISSING"},
+		{"named logger", func(l *Logger) { l.Named("db").With("a", 1).Info("m") }, "[INFO] m logger=db a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			tt.log(l)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLevelFollowsBase(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	w := l.With("a", 1)
+
+	if err := w.SetLogLevel(LOG_DEBUG); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.LogLevel(); got != LOG_DEBUG {
+		t.Errorf("level set through With: base level %d, want %d", got, LOG_DEBUG)
+	}
+	if got := w.DecreaseVerbosity(); got != LOG_INFO || l.LogLevel() != LOG_INFO {
+		t.Errorf("DecreaseVerbosity through With: returned %d, base level %d, want %d", got, l.LogLevel(), LOG_INFO)
+	}
+
+	if err := l.SetLogLevel(LOG_ERROR); err != nil {
+		t.Fatal(err)
+	}
+	if got := w.LogLevel(); got != LOG_ERROR {
+		t.Errorf("level of With logger after base change %d, want %d", got, LOG_ERROR)
+	}
+	w.Debug("debug")
+	w.Info("info")
+	w.Error("error")
+	if got, want := buf.String(), "[ERROR] error a=1\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestChildSettingsApplyToBase(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(l *Logger)
+		want string // expected line
+		n    int    // expected number of lines
+	}{
+		{"SetQuoteMessage", func(l *Logger) { l.SetQuoteMessage(true) }, `[INFO] "m" a=1 b=2`, 2},
+		{"SetNumericPrefix", func(l *Logger) { l.SetNumericPrefix(true) }, "<6>[INFO] m a=1 b=2", 2},
+		{"SetFacility", func(l *Logger) { l.SetNumericPrefix(true); _ = l.SetFacility(16) }, "<134>[INFO] m a=1 b=2", 2},
+		{"SetFieldOrder", func(l *Logger) { l.SetFieldOrder([]string{"b"}) }, "[INFO] m b=2 a=1", 2},
+		{"SetTokenBucket", func(l *Logger) { l.SetTokenBucket(LOG_INFO, 1, 1) }, "[INFO] m a=1 b=2", 1},
+		{"SetOutputFunc", func(l *Logger) { l.SetOutputFunc(func(int) io.Writer { return ioutil.Discard }) }, "", 0},
+	}
+	for _, tt := range tests {
+		for _, child := range []string{"With", "Named"} {
+			t.Run(tt.name+" on "+child, func(t *testing.T) {
+				l, buf := newTestLogger(t, LOG_DEBUG)
+				c := l.With()
+				if child == "Named" {
+					c = l.Named("x").Named("y")
+				}
+				tt.set(c)
+				l.Infow("m", "a", 1, "b", 2)
+				l.Infow("m", "a", 1, "b", 2)
+				got := lines(buf)
+				if len(got) != tt.n {
+					t.Fatalf("output %q, want %d lines", got, tt.n)
+				}
+				for _, line := range got {
+					if line != tt.want {
+						t.Errorf("line %q, want %q", line, tt.want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestChildSettersConcurrentWithLogging(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	setters := []func(c *Logger, i int){
+		func(c *Logger, i int) { c.SetTimeFormat([]string{"", time.RFC3339}[i%2]) },
+		func(c *Logger, i int) { c.SetQuoteMessage(i%2 == 0) },
+		func(c *Logger, i int) { c.SetNumericPrefix(i%2 == 0) },
+		func(c *Logger, i int) { c.SetFieldOrder([]string{"b"}) },
+		func(c *Logger, i int) { c.SetTimeKey("ts") },
+		func(c *Logger, i int) { c.SetElapsedTimestamps(i%2 == 0) },
+		func(c *Logger, i int) { c.SetDeterministic(i%2 == 0) },
+		func(c *Logger, i int) { c.SetCaller(i%2 == 0) },
+		func(c *Logger, i int) { c.SetCallerFunc(i%2 == 0) },
+		func(c *Logger, i int) { c.SetMaxDepth(i % 4) },
+		func(c *Logger, i int) { c.SetTrailingMapFields(i%2 == 0) },
+		func(c *Logger, i int) { c.SetStackDepth(i % 4) },
+		func(c *Logger, i int) { c.SetFatalLevel(i % 8) },
+		func(c *Logger, i int) { c.SetLineEnding([]string{"\n", "\r\n"}[i%2]) },
+	}
+
+	var wg sync.WaitGroup
+	for _, set := range setters {
+		wg.Add(1)
+		go func(set func(c *Logger, i int)) {
+			defer wg.Done()
+			c := l.Named("setter").With("k", "v")
+			for i := 0; i < 100; i++ {
+				set(c, i)
+			}
+		}(set)
+	}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := l.With("a", 1)
+			for i := 0; i < 100; i++ {
+				c.Infow("m", "b", []int{i}, "c", map[string]interface{}{"d": i})
+				c.ErrorStack("e")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLevelSettersStress(t *testing.T) {
+	const goroutines, iterations = 8, 500
+	l, buf := newTestLogger(t, LOG_INFO)
+	db, query := l.Named("db"), l.Named("db.query")
+	debug := query.Prepare(LOG_DEBUG, "prepared")
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(2)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				lvl := (g + i) % 8
+				switch i % 4 {
+				case 0:
+					l.SetLogLevel(lvl)
+				case 1:
+					l.SetComponentLevel("db", lvl)
+				case 2:
+					db.SetLogLevel(lvl)
+				case 3:
+					l.SetComponentLevel("db.query", -1)
+				}
+			}
+		}(g)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				db.Info("db")
+				query.Debug("query")
+				debug()
+				if lvl := query.LogLevel(); lvl < LOG_EMERG || lvl > LOG_DEBUG {
+					t.Errorf("LogLevel() = %d", lvl)
+					return
+				}
+			}
+		}()
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(30 * time.Second):
+		t.Fatal("level setters deadlocked")
+	}
+
+	for _, line := range lines(buf) {
+		switch line {
+		case "[INFO] db logger=db", "[DEBUG] query logger=db.query", "[DEBUG] prepared logger=db.query":
+		default:
+			t.Fatalf("corrupted line %q", line)
+		}
+	}
+
+	// Cached levels must follow the final settings.
+	buf.Reset()
+	l.SetLogLevel(LOG_ERROR)
+	l.SetComponentLevel("db", LOG_DEBUG)
+	l.SetComponentLevel("db.query", -1)
+	if got := query.LogLevel(); got != LOG_DEBUG {
+		t.Errorf("db.query level = %d, want %d", got, LOG_DEBUG)
+	}
+	query.Debug("query")
+	debug()
+	l.Info("root")
+	want := "[DEBUG] query logger=db.query\n[DEBUG] prepared logger=db.query"
+	if got := strings.Join(lines(buf), "\n"); got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
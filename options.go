@@ -0,0 +1,64 @@
+package twigsnake
+
+import "time"
+
+// Option configures Logger being created by New.
+type Option func(*Logger)
+
+// WithFlags sets output flags of underlying loggers of all levels (see SetFlags), overriding the default
+// log.Ldate|log.Ltime|log.Lmsgprefix.
+func WithFlags(flag int) Option {
+	return func(l *Logger) {
+		l.SetFlags(flag)
+	}
+}
+
+// WithPrefixes sets prefixes of underlying loggers of levels present in prefixes, e.g. {LOG_ERROR: "E "}; prefixes of
+// other levels stay default. Invalid levels are ignored.
+func WithPrefixes(prefixes map[int]string) Option {
+	return func(l *Logger) {
+		lgs := l.loggers()
+		for lvl, prefix := range prefixes {
+			if checkLogLevel(lvl) == nil {
+				lgs[lvl].SetPrefix(prefix)
+			}
+		}
+	}
+}
+
+// WithTimeFormat sets layout of timestamps in text formats (see SetTimeFormat).
+func WithTimeFormat(layout string) Option {
+	return func(l *Logger) {
+		l.SetTimeFormat(layout)
+	}
+}
+
+// WithFormat sets one of the built-in formats, e.g. FormatJSON for one JSON object per message (see SetFormat).
+func WithFormat(f Format) Option {
+	return func(l *Logger) {
+		l.SetFormat(f)
+	}
+}
+
+// WithCaller enables or disables reporting of the call site as file:line (see SetCaller).
+func WithCaller(enabled bool) Option {
+	return func(l *Logger) {
+		l.SetCaller(enabled)
+	}
+}
+
+// WithClock sets the function used to obtain current time (see SetClock), e.g. a frozen clock for golden-output tests.
+func WithClock(clock func() time.Time) Option {
+	return func(l *Logger) {
+		l.SetClock(clock)
+	}
+}
+
+// WithRateLimit lets at most perSecond messages of given level pass per second, with bursts of up to perSecond
+// messages; the rest are dropped and reported with "(suppressed N messages)" lines (see SetTokenBucket). Non-positive
+// perSecond leaves the level unlimited.
+func WithRateLimit(level, perSecond int) Option {
+	return func(l *Logger) {
+		l.SetTokenBucket(level, float64(perSecond), perSecond)
+	}
+}
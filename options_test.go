@@ -0,0 +1,74 @@
+package twigsnake
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDefaults(t *testing.T) {
+	l, err := New(LOG_INFO, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefixes := [8]string{"[EMERG] ", "[ALERT] ", "[CRIT] ", "[ERROR] ", "[WARN] ", "[NOTICE] ", "[INFO] ", "[DEBUG] "}
+	for lvl, lg := range l.loggers() {
+		if got, want := lg.Flags(), log.Ldate|log.Ltime|log.Lmsgprefix; got != want {
+			t.Errorf("level %d: flags %#x, want %#x", lvl, got, want)
+		}
+		if got := lg.Prefix(); got != prefixes[lvl] {
+			t.Errorf("level %d: prefix %q, want %q", lvl, got, prefixes[lvl])
+		}
+	}
+}
+
+func TestOptions(t *testing.T) {
+	const defaultFlags = log.Ldate | log.Ltime | log.Lmsgprefix
+	tests := []struct {
+		name     string
+		opts     []Option
+		flags    int            // expected flags of all levels
+		prefixes map[int]string // expected prefixes of some levels
+	}{
+		{"WithFlags propagates to all levels", []Option{WithFlags(log.LUTC | log.Lshortfile)},
+			log.LUTC | log.Lshortfile, map[int]string{LOG_EMERG: "[EMERG] ", LOG_DEBUG: "[DEBUG] "}},
+		{"WithPrefixes overrides given levels only", []Option{WithPrefixes(map[int]string{LOG_ERROR: "E ", 42: "x"})},
+			defaultFlags, map[int]string{LOG_CRIT: "[CRIT] ", LOG_ERROR: "E ", LOG_WARN: "[WARN] "}},
+		{"later option wins", []Option{WithFlags(log.Ltime), WithFlags(log.Lmsgprefix)},
+			log.Lmsgprefix, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(LOG_INFO, nil, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lgs := l.loggers()
+			for lvl, lg := range lgs {
+				if got := lg.Flags(); got != tt.flags {
+					t.Errorf("level %d: flags %#x, want %#x", lvl, got, tt.flags)
+				}
+			}
+			for lvl, want := range tt.prefixes {
+				if got := lgs[lvl].Prefix(); got != want {
+					t.Errorf("level %d: prefix %q, want %q", lvl, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWithTimeFormat(t *testing.T) {
+	var buf bytes.Buffer
+	clock := WithClock(func() time.Time { return testTime })
+	l, err := New(LOG_INFO, &buf, clock, WithTimeFormat(time.RFC3339), WithFlags(log.Ltime|log.LUTC|log.Lmsgprefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("m")
+	if got, want := strings.TrimSuffix(buf.String(), "\n"), "2021-03-05T14:30:15Z [INFO] m"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package twigsnake
+
+import "io"
+
+// SetOutputLevels restricts writer w to messages of listed levels only, whichever way they get there: as a level's
+// output, alert sink, detail output or sink. This is a guardrail for destinations which must never see certain
+// messages, e.g. an audit file which must not receive debug output even if it gets misconfigured as debug output.
+// Invalid levels are ignored. Calling SetOutputLevels without levels lifts the restriction. Writers are matched by
+// identity, so restricting one writer never affects another writer of the same type; writers of non-comparable types,
+// such as func adapters, must be passed as the same interface value they were set up with (see writerKey).
+func (l *Logger) SetOutputLevels(w io.Writer, levels ...int) {
+	var mask uint8
+	for _, lvl := range levels {
+		if checkLogLevel(lvl) == nil {
+			mask |= 1 << uint(lvl)
+		}
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(levels) == 0 {
+		delete(r.outputLevels, writerKey(w))
+		return
+	}
+	if r.outputLevels == nil {
+		r.outputLevels = make(map[interface{}]uint8)
+	}
+	r.outputLevels[writerKey(w)] = mask
+}
+
+// outputAccepts reports whether w accepts messages of level lvl. Must be called with l.mu held.
+func (l *Logger) outputAccepts(w io.Writer, lvl int) bool {
+	if len(l.outputLevels) == 0 {
+		return true
+	}
+	mask, ok := l.outputLevels[writerKey(w)]
+	return !ok || mask&(1<<uint(lvl)) != 0
+}
@@ -0,0 +1,43 @@
+package twigsnake
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetOutputLevels(t *testing.T) {
+	var a, b bytes.Buffer
+	wa, wb := bufferWriter(&a), bufferWriter(&b)
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.ErrorLogger.SetOutput(wa)
+	l.DebugLogger.SetOutput(wa)
+	l.InfoLogger.SetOutput(wb)
+	l.SetOutputLevels(wa, LOG_ERROR)
+
+	l.Errorln("error")
+	l.Debugln("debug")
+	l.Infoln("info")
+
+	tests := []struct {
+		name string
+		buf  *bytes.Buffer
+		want string
+	}{
+		{"restricted writer", &a, "[ERROR] error\n"},
+		{"other writer of the same type", &b, "[INFO] info\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	l.SetOutputLevels(wa)
+	l.Debugln("debug")
+	if !strings.HasSuffix(a.String(), "[DEBUG] debug\n") {
+		t.Errorf("restriction not lifted: %q", a.String())
+	}
+}
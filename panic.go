@@ -0,0 +1,32 @@
+package twigsnake
+
+import "fmt"
+
+// Panic prints critical message, flushes the logger (see Flush) and panics with the message text, like log.Panic does,
+// so recovering code can inspect exactly what was logged. Since it panics anyway, the message is printed regardless of
+// logging level, just like emergency messages are. Handles arguments in the same manner as log.Print.
+func (l *Logger) Panic(v ...interface{}) {
+	v, fields := l.trailingFields(v)
+	s := fmt.Sprint(v...)
+	l.output(LOG_CRIT, s, fields)
+	l.base().Flush()
+	panic(s)
+}
+
+// Panicf is like Panic, but handles arguments in the same manner as log.Printf.
+func (l *Logger) Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	l.output(LOG_CRIT, s, nil)
+	l.base().Flush()
+	panic(s)
+}
+
+// Panicln is like Panic, but handles arguments in the same manner as log.Println. Like with log.Panicln, the panic
+// value ends with a line break.
+func (l *Logger) Panicln(v ...interface{}) {
+	v, fields := l.trailingFields(v)
+	s := fmt.Sprintln(v...)
+	l.output(LOG_CRIT, s, fields)
+	l.base().Flush()
+	panic(s)
+}
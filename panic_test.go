@@ -0,0 +1,38 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPanic(t *testing.T) {
+	tests := []struct {
+		name      string
+		panic     func(l *Logger)
+		wantValue string
+		wantLine  string
+	}{
+		{"Panic", func(l *Logger) { l.Panic("disk ", 3, " failed") }, "disk 3 failed", "[CRIT] disk 3 failed"},
+		{"Panicf", func(l *Logger) { l.Panicf("disk %d failed", 3) }, "disk 3 failed", "[CRIT] disk 3 failed"},
+		{"Panicln", func(l *Logger) { l.Panicln("disk", 3, "failed") }, "disk 3 failed\n", "[CRIT] disk 3 failed"},
+		{"named", func(l *Logger) { l.Named("db").Panic("x") }, "x", "[CRIT] x logger=db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Logging level below critical must not suppress the message.
+			l, buf := newTestLogger(t, LOG_EMERG)
+			func() {
+				defer func() {
+					if r := recover(); r != tt.wantValue {
+						t.Errorf("recovered %#v, want %q", r, tt.wantValue)
+					}
+				}()
+				tt.panic(l)
+				t.Error("no panic")
+			}()
+			if got := strings.Join(lines(buf), "\n"); got != tt.wantLine {
+				t.Errorf("output %q, want %q", got, tt.wantLine)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package twigsnake
+
+import "sync/atomic"
+
+// Policy defines how APIs taking severity level treat values outside of LOG_EMERG..LOG_DEBUG range.
+type Policy int32
+
+// Invalid level policies:
+const (
+	// PolicyError rejects invalid levels: constructors and setters return error, messages are dropped. It is the
+	// default.
+	PolicyError Policy = iota
+	// PolicyClamp replaces invalid level with the nearest valid one: LOG_EMERG for negative levels, LOG_DEBUG for too
+	// big ones.
+	PolicyClamp
+	// PolicyIgnore makes setters no-ops returning nil and drops messages. Constructors still return error, since they
+	// have no setting to keep.
+	PolicyIgnore
+)
+
+// invalidLevelPolicy holds current Policy, accessed atomically.
+var invalidLevelPolicy int32
+
+// SetInvalidLevelPolicy sets policy for invalid severity levels passed to any function or method of the package:
+// New and other constructors, SetLogLevel, SetTokenBucket and methods printing messages of given level such as LogAttrs,
+// Debounce, Banner, Section, Transition and Prepare. SetComponentLevel is not affected, since invalid level has special
+// meaning there.
+func SetInvalidLevelPolicy(p Policy) {
+	atomic.StoreInt32(&invalidLevelPolicy, int32(p))
+}
+
+// applyLevelPolicy applies current invalid level policy to lvl. It returns the level to use, or ok false if the call
+// must be skipped; in that case err is non-nil with PolicyError.
+func applyLevelPolicy(lvl int) (level int, ok bool, err error) {
+	if err = checkLogLevel(lvl); err == nil {
+		return lvl, true, nil
+	}
+	switch Policy(atomic.LoadInt32(&invalidLevelPolicy)) {
+	case PolicyClamp:
+		if lvl < LOG_EMERG {
+			return LOG_EMERG, true, nil
+		}
+		return LOG_DEBUG, true, nil
+	case PolicyIgnore:
+		return lvl, false, nil
+	}
+	return lvl, false, err
+}
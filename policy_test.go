@@ -0,0 +1,58 @@
+package twigsnake
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestInvalidLevelPolicy(t *testing.T) {
+	defer SetInvalidLevelPolicy(PolicyError)
+
+	tests := []struct {
+		policy    Policy
+		name      string
+		newLevel  int // level of logger created by New with level 100, or -1 if New fails
+		setErr    bool
+		setLevels [2]int // level after SetLogLevel(-1) and SetLogLevel(8)
+		output    []string
+	}{
+		{PolicyError, "error", -1, true, [2]int{LOG_INFO, LOG_INFO}, nil},
+		{PolicyClamp, "clamp", LOG_DEBUG, false, [2]int{LOG_EMERG, LOG_DEBUG}, []string{"[EMERG] low", "[DEBUG] high"}},
+		{PolicyIgnore, "ignore", -1, false, [2]int{LOG_INFO, LOG_INFO}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetInvalidLevelPolicy(tt.policy)
+
+			l, err := New(100, nil)
+			if tt.newLevel < 0 {
+				if err == nil || l != nil {
+					t.Errorf("New(100) = %v, %v; want error", l, err)
+				}
+			} else if err != nil {
+				t.Errorf("New(100) failed: %v", err)
+			} else if got := l.LogLevel(); got != tt.newLevel {
+				t.Errorf("New(100) level %d, want %d", got, tt.newLevel)
+			}
+
+			l, buf := newTestLogger(t, LOG_INFO)
+			for i, lvl := range []int{-1, 8} {
+				if err := l.SetLogLevel(lvl); (err != nil) != tt.setErr {
+					t.Errorf("SetLogLevel(%d) error %v, want error %v", lvl, err, tt.setErr)
+				}
+				if got := l.LogLevel(); got != tt.setLevels[i] {
+					t.Errorf("level %d after SetLogLevel(%d), want %d", got, lvl, tt.setLevels[i])
+				}
+			}
+
+			// Messages of invalid levels are dropped or clamped as well.
+			l.SetLogLevel(LOG_DEBUG)
+			l.LogAttrs(context.Background(), -5, "low")
+			l.LogAttrs(context.Background(), 42, "high")
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.output, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.output, "\n"))
+			}
+		})
+	}
+}
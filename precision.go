@@ -0,0 +1,70 @@
+package twigsnake
+
+import (
+	"log"
+	"time"
+)
+
+// Precision is the precision of timestamps in text formats.
+type Precision int
+
+// Timestamp precisions:
+const (
+	PrecisionSecond Precision = iota + 1 // 15:04:05
+	PrecisionMilli                       // 15:04:05.000
+	PrecisionMicro                       // 15:04:05.000000, the same as log.Lmicroseconds flag
+	PrecisionNano                        // 15:04:05.000000000
+)
+
+// timeLayouts holds time layouts of precisions, indexed by precision.
+var timeLayouts = [...]string{
+	PrecisionSecond: "15:04:05 ",
+	PrecisionMilli:  "15:04:05.000 ",
+	PrecisionMicro:  "15:04:05.000000 ",
+	PrecisionNano:   "15:04:05.000000000 ",
+}
+
+// fieldTimeLayouts holds RFC 3339 layouts of time field values matching precisions, indexed by precision.
+var fieldTimeLayouts = [...]string{
+	PrecisionSecond: time.RFC3339,
+	PrecisionMilli:  "2006-01-02T15:04:05.000Z07:00",
+	PrecisionMicro:  "2006-01-02T15:04:05.000000Z07:00",
+	PrecisionNano:   "2006-01-02T15:04:05.000000000Z07:00",
+}
+
+// fieldTimeLayout returns layout of time field values of entry e in text formats: its time format if set, otherwise
+// RFC 3339 with precision of e, or empty string if neither is set.
+func fieldTimeLayout(e *Entry) string {
+	if e.TimeFormat != "" {
+		return e.TimeFormat
+	}
+	if e.TimePrecision > 0 {
+		return fieldTimeLayouts[e.TimePrecision]
+	}
+	return ""
+}
+
+// SetLevelTimePrecision sets precision of timestamps of messages of given level in text formats, e.g. microseconds for
+// debug messages while other levels stay at seconds. It adjusts flags of the level's standard logger, enabling
+// log.Ltime and enabling log.Lmicroseconds for PrecisionMicro or disabling it otherwise; millisecond and nanosecond
+// precisions, which log flags can't express, are rendered by the logger itself. Changing log.Lmicroseconds flag
+// afterwards takes precedence again. Invalid levels and precisions are ignored.
+func (l *Logger) SetLevelTimePrecision(level int, p Precision) {
+	if checkLogLevel(level) != nil || p < PrecisionSecond || p > PrecisionNano {
+		return
+	}
+
+	r := l.base()
+	lg := r.loggers()[level]
+	flags := lg.Flags() | log.Ltime
+	if p == PrecisionMicro {
+		flags |= log.Lmicroseconds
+	} else {
+		flags &^= log.Lmicroseconds
+	}
+	lg.SetFlags(flags)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.precision[level] = p
+}
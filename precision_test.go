@@ -0,0 +1,33 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetLevelTimePrecision(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		p     Precision
+		want  []string
+	}{
+		{"second", LOG_INFO, PrecisionSecond, []string{"14:30:15 [INFO] i", "[DEBUG] d"}},
+		{"milli", LOG_INFO, PrecisionMilli, []string{"14:30:15.123 [INFO] i", "[DEBUG] d"}},
+		{"micro", LOG_INFO, PrecisionMicro, []string{"14:30:15.123456 [INFO] i", "[DEBUG] d"}},
+		{"nano", LOG_DEBUG, PrecisionNano, []string{"[INFO] i", "14:30:15.123456000 [DEBUG] d"}},
+		{"invalid level", 8, PrecisionMilli, []string{"[INFO] i", "[DEBUG] d"}},
+		{"invalid precision", LOG_INFO, PrecisionNano + 1, []string{"[INFO] i", "[DEBUG] d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.SetLevelTimePrecision(tt.level, tt.p)
+			l.Info("i")
+			l.Debug("d")
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
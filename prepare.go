@@ -0,0 +1,63 @@
+package twigsnake
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Prepare returns a function printing messages on given level, formatted according to format in the same manner as
+// log.Printf. It is meant for hot loops logging the same kind of message over and over: level is validated once, the
+// format is inspected once and formatting is skipped altogether for formats without verbs. Whether the level is enabled
+// is cached along with the generation of logging levels it was checked at, so calls only pay for a single atomic load
+// until levels change, and level changes still take effect immediately. For invalid level a no-op function is returned,
+// unless SetInvalidLevelPolicy says otherwise.
+func (l *Logger) Prepare(level int, format string) func(args ...interface{}) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok {
+		return func(args ...interface{}) {}
+	}
+
+	p := &preparedLevel{l: l, level: level}
+	if !strings.Contains(format, "%") {
+		return func(args ...interface{}) {
+			if !p.enabled() {
+				return
+			}
+			if len(args) == 0 {
+				l.output(level, format, nil)
+				return
+			}
+			l.output(level, fmt.Sprintf(format, args...), nil)
+		}
+	}
+
+	return func(args ...interface{}) {
+		if p.enabled() {
+			l.output(level, fmt.Sprintf(format, args...), nil)
+		}
+	}
+}
+
+// preparedLevel caches whether level of a prepared function is enabled.
+type preparedLevel struct {
+	cache uint64 // levelGen+1 the check was made at shifted left by one, with the result in the lowest bit; atomic
+	l     *Logger
+	level int
+}
+
+// enabled reports whether p.level is enabled for p.l, the same as EffectiveEnabled does, consulting logging levels only
+// when they have changed since the last call.
+func (p *preparedLevel) enabled() bool {
+	gen := atomic.LoadUint64(&p.l.base().levelGen)
+	if c := atomic.LoadUint64(&p.cache); c>>1 == gen+1 {
+		return c&1 != 0
+	}
+	ok := p.l.EffectiveEnabled(p.level)
+	c := (gen + 1) << 1
+	if ok {
+		c |= 1
+	}
+	atomic.StoreUint64(&p.cache, c)
+	return ok
+}
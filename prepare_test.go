@@ -0,0 +1,85 @@
+package twigsnake
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  int
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{"format with verbs", LOG_INFO, "n=%d", []interface{}{1}, "[INFO] n=1"},
+		{"format without verbs", LOG_WARN, "plain", nil, "[WARN] plain"},
+		{"extra arguments without verbs", LOG_WARN, "plain", []interface{}{1}, "[WARN] plain%!(EXTRA int=1)"},
+		{"disabled level", LOG_DEBUG, "n=%d", []interface{}{1}, ""},
+		{"invalid level", 42, "n=%d", []interface{}{1}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			l.Prepare(tt.level, tt.format)(tt.args...)
+			if got := strings.Join(lines(buf), "\n"); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareFollowsLevelChanges(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	named := l.Named("db")
+	tests := []struct {
+		name    string
+		change  func()
+		printed bool
+	}{
+		{"initial level", func() {}, false},
+		{"SetLogLevel", func() { l.SetLogLevel(LOG_DEBUG) }, true},
+		{"SetComponentLevel", func() { l.SetComponentLevel("db", LOG_INFO) }, false},
+		{"DecreaseVerbosity", func() { l.SetComponentLevel("db", LOG_DEBUG); l.DecreaseVerbosity() }, true},
+		{"SetEnabledLevels", func() { l.SetEnabledLevels(LOG_ERROR) }, false},
+		{"EnableLevel", func() { l.EnableLevel(LOG_DEBUG) }, true},
+		{"DisableLevel", func() { l.DisableLevel(LOG_DEBUG) }, false},
+	}
+	debug := named.Prepare(LOG_DEBUG, "x")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			tt.change()
+			debug()
+			if got := buf.Len() > 0; got != tt.printed {
+				t.Errorf("printed = %v, want %v", got, tt.printed)
+			}
+		})
+	}
+}
+
+func BenchmarkPrepare(b *testing.B) {
+	for _, lvl := range []int{LOG_INFO, LOG_WARN} {
+		name := "enabled"
+		if lvl < LOG_INFO {
+			name = "disabled"
+		}
+		b.Run(name+"/Prepare", func(b *testing.B) {
+			l, _ := New(lvl, ioutil.Discard)
+			info := l.Prepare(LOG_INFO, "n=%d")
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				info(i)
+			}
+		})
+		b.Run(name+"/Infof", func(b *testing.B) {
+			l, _ := New(lvl, ioutil.Discard)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Infof("n=%d", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package twigsnake
+
+import "os"
+
+// Size limit and number of backups of log files created by NewConsoleAndFile.
+const (
+	presetMaxBytes   = 10 << 20
+	presetMaxBackups = 5
+)
+
+// NewConsoleAndFile creates new Logger instance with specified logging level set up the way most services need it:
+// messages go to standard error as text, colored by severity if standard error is a terminal and NO_COLOR environment
+// variable is not set, and to the file at path as JSON lines (see FormatJSON). The file is rotated when it reaches
+// 10 MiB, keeping 5 backups (see RotatingFileWriter). Both destinations are configured as sinks, so SetSinks replaces
+// them. Close the logger to close the file.
+func NewConsoleAndFile(lvl int, path string) (*Logger, error) {
+	l, err := New(lvl, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	file, err := NewRotatingFileWriter(path, presetMaxBytes, presetMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	console := FormatText
+	if isTerminal(os.Stderr) && os.Getenv("NO_COLOR") == "" {
+		console = FormatColor
+	}
+	l.SetSinks(
+		Sink{Formatter: console.Formatter(), Writer: os.Stderr, MinLevel: LOG_DEBUG},
+		Sink{Formatter: FormatJSON.Formatter(), Writer: file, MinLevel: LOG_DEBUG},
+	)
+	return l, nil
+}
+
+// isTerminal reports whether f is a character device, which is the case for terminals.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
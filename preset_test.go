@@ -0,0 +1,63 @@
+package twigsnake
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewConsoleAndFile(t *testing.T) {
+	dir := t.TempDir()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+	defer func(f *os.File) { os.Stderr = f }(os.Stderr)
+	os.Stderr = stderr // a regular file, so the console gets plain text
+
+	path := filepath.Join(dir, "app.log")
+	l, err := NewConsoleAndFile(LOG_INFO, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetFlags(log.Lmsgprefix)
+	l.Infow("started", "port", 8080)
+	l.Debug("hidden")
+	l.SetLogLevel(LOG_DEBUG)
+	l.Debug("shown")
+	l.Error("failed")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	console, err := ioutil.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(console), "[INFO] started port=8080\n[DEBUG] shown\n[ERROR] failed\n"; got != want {
+		t.Errorf("console %q, want %q", got, want)
+	}
+
+	file, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, obj := range decodeNDJSON(t, bytes.NewReader(file)) {
+		if _, ok := obj["time"]; !ok {
+			t.Errorf("file line %v without time", obj)
+		}
+		delete(obj, "time")
+		got = append(got, fmt.Sprint(obj))
+	}
+	want := []string{"map[level:info msg:started port:8080]", "map[level:debug msg:shown]", "map[level:error msg:failed]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("file %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package twigsnake
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// SetDefaultLevel sets severity level of messages printed with Print, Printf and Println, LOG_INFO by default. Invalid
+// level is clamped if PolicyClamp is set with SetInvalidLevelPolicy and ignored otherwise. Named loggers share the
+// default level of the logger they were created from.
+func (l *Logger) SetDefaultLevel(level int) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok {
+		return
+	}
+	atomic.StoreInt32(&l.base().printLevel, int32(level))
+}
+
+// DefaultLevel returns severity level of messages printed with Print, Printf and Println.
+func (l *Logger) DefaultLevel() int {
+	return int(atomic.LoadInt32(&l.base().printLevel))
+}
+
+// Print prints message on the default level (see SetDefaultLevel). Together with Printf and Println it makes Logger a
+// near drop-in replacement for log.Logger, easing migration of code which doesn't choose severity at every call site.
+// Handles arguments in the same manner as log.Print.
+func (l *Logger) Print(v ...interface{}) {
+	if lvl := l.DefaultLevel(); l.EffectiveEnabled(lvl) {
+		v, fields := l.trailingFields(v)
+		l.output(lvl, fmt.Sprint(v...), fields)
+	}
+}
+
+// Printf prints message on the default level (see SetDefaultLevel). Handles arguments in the same manner as log.Printf.
+func (l *Logger) Printf(format string, v ...interface{}) {
+	if lvl := l.DefaultLevel(); l.EffectiveEnabled(lvl) {
+		l.output(lvl, fmt.Sprintf(format, v...), nil)
+	}
+}
+
+// Println prints message on the default level (see SetDefaultLevel). Handles arguments in the same manner as log.Println.
+func (l *Logger) Println(v ...interface{}) {
+	if lvl := l.DefaultLevel(); l.EffectiveEnabled(lvl) {
+		v, fields := l.trailingFields(v)
+		l.output(lvl, fmt.Sprintln(v...), fields)
+	}
+}
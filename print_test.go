@@ -0,0 +1,32 @@
+package twigsnake
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	l.Print("a", 1)
+	l.Printf("b %d", 2)
+	l.Println("c", 3)
+
+	// Named loggers share the default level.
+	l.Named("db").SetDefaultLevel(LOG_WARN)
+	l.Print("warn")
+	l.Named("db").Printf("named %s", "warn")
+
+	l.SetDefaultLevel(100) // invalid, ignored
+	l.Print("still warn")
+	l.SetDefaultLevel(LOG_DEBUG)
+	l.Print("hidden") // below the logging level
+
+	want := []string{"[INFO] a1", "[INFO] b 2", "[INFO] c 3", "[WARN] warn", "[WARN] named warn logger=db",
+		"[WARN] still warn"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if got := l.DefaultLevel(); got != LOG_DEBUG {
+		t.Errorf("DefaultLevel() = %d, want %d", got, LOG_DEBUG)
+	}
+}
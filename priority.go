@@ -0,0 +1,48 @@
+package twigsnake
+
+import (
+	"errors"
+	"strconv"
+)
+
+// SetFacility sets syslog facility code (0 to 23, see RFC 5424 section 6.2.1) used to compute message priority values.
+// Default facility is 0 (kernel messages), which makes priority value equal to severity level.
+func (l *Logger) SetFacility(facility int) error {
+	if facility < 0 || facility > 23 {
+		return errors.New("invalid syslog facility")
+	}
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.facility = facility
+	return nil
+}
+
+// SetNumericPrefix enables or disables PRI-style numeric tag at the beginning of every line, such as "<3>" for error
+// messages. The number is computed as facility*8+severity, like in syslog. This is a lightweight alternative to full
+// syslog formatting for consumers which only need the severity, e.g. systemd-journald reading service's stderr.
+func (l *Logger) SetNumericPrefix(enabled bool) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.numericPrefix = enabled
+}
+
+// auditFacility is the syslog facility of audit messages, "log audit" (see RFC 5424 section 6.2.1).
+const auditFacility = 13
+
+// appendEntryPriority appends PRI part of syslog message for entry e logged with given facility to buf. Audit entries
+// (see Entry.Audit) are reported as notices of the log audit facility.
+func appendEntryPriority(buf []byte, e *Entry, facility int) []byte {
+	if e.Audit {
+		return appendPriority(buf, auditFacility, LOG_NOTICE)
+	}
+	return appendPriority(buf, facility, e.Level)
+}
+
+// appendPriority appends PRI part of syslog message, e.g. "<11>", to buf.
+func appendPriority(buf []byte, facility, severity int) []byte {
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(facility*8+severity), 10)
+	return append(buf, '>')
+}
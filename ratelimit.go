@@ -0,0 +1,61 @@
+package twigsnake
+
+import "time"
+
+// tokenBucket limits the rate of messages: every message takes a token, tokens are replenished at constant rate up to
+// burst.
+type tokenBucket struct {
+	rate       float64 // tokens per second
+	burst      float64
+	tokens     float64
+	last       time.Time
+	suppressed int
+}
+
+// allow takes a token if one is available at time now, otherwise counts the message as suppressed.
+func (b *tokenBucket) allow(now time.Time) bool {
+	if !b.last.IsZero() {
+		if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+			b.tokens += elapsed * b.rate
+			if b.tokens > b.burst {
+				b.tokens = b.burst
+			}
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		b.suppressed++
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// takeSuppressed returns the number of messages suppressed since the previous call.
+func (b *tokenBucket) takeSuppressed() int {
+	n := b.suppressed
+	b.suppressed = 0
+	return n
+}
+
+// SetTokenBucket limits the rate of messages of given level with a token bucket: up to burst messages may pass at once,
+// while sustained rate is bounded by rate messages per second. Messages exceeding the limit are dropped; when messages
+// start passing again, a "(suppressed N messages)" line reports how many were lost. Buckets are refilled according to
+// the logger's clock. Non-positive rate or burst removes the limit. Invalid levels are ignored unless PolicyClamp is in
+// effect (see SetInvalidLevelPolicy).
+func (l *Logger) SetTokenBucket(level int, rate float64, burst int) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok {
+		return
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rate <= 0 || burst <= 0 {
+		r.buckets[level] = nil
+		return
+	}
+	r.buckets[level] = &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
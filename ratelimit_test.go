@@ -0,0 +1,116 @@
+package twigsnake
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	type step struct {
+		after time.Duration // time passed since the previous step
+		n     int           // number of messages logged
+	}
+	tests := []struct {
+		name      string
+		perSecond int
+		steps     []step
+		want      []string
+	}{
+		{"within limit", 3, []step{{0, 3}}, []string{"[ERROR] m", "[ERROR] m", "[ERROR] m"}},
+		{"burst suppressed", 2, []step{{0, 5}}, []string{"[ERROR] m", "[ERROR] m"}},
+		{"suppression reported", 2, []step{{0, 5}, {time.Second, 1}},
+			[]string{"[ERROR] m", "[ERROR] m", "[ERROR] (suppressed 3 messages)", "[ERROR] m"}},
+		{"partial refill", 2, []step{{0, 2}, {500 * time.Millisecond, 2}, {500 * time.Millisecond, 1}},
+			[]string{"[ERROR] m", "[ERROR] m", "[ERROR] m", "[ERROR] (suppressed 1 messages)", "[ERROR] m"}},
+		{"refill capped at burst", 1, []step{{time.Hour, 3}}, []string{"[ERROR] m"}},
+		{"no limit", 0, []step{{0, 4}}, []string{"[ERROR] m", "[ERROR] m", "[ERROR] m", "[ERROR] m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := testTime
+			l, buf := newTestLogger(t, LOG_DEBUG, WithClock(func() time.Time { return now }),
+				WithRateLimit(LOG_ERROR, tt.perSecond))
+			for _, s := range tt.steps {
+				now = now.Add(s.after)
+				for i := 0; i < s.n; i++ {
+					l.Error("m")
+				}
+			}
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitOtherLevels(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithRateLimit(LOG_ERROR, 1))
+	l.Error("e")
+	l.Error("e")
+	l.Warn("w")
+	l.Warn("w")
+	want := []string{"[ERROR] e", "[WARN] w", "[WARN] w"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestRateLimitConcurrent(t *testing.T) {
+	const goroutines, messages, perSecond = 8, 50, 10
+	l, buf := newTestLogger(t, LOG_DEBUG, WithRateLimit(LOG_ERROR, perSecond))
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < messages; i++ {
+				l.Error("m")
+			}
+		}()
+	}
+	wg.Wait()
+	// The clock is frozen, so the bucket is never refilled.
+	if got := len(lines(buf)); got != perSecond {
+		t.Errorf("%d messages passed, want %d", got, perSecond)
+	}
+}
+
+func TestLimitsApplyToAllPaths(t *testing.T) {
+	past := testTime.Add(-24 * time.Hour)
+	tests := []struct {
+		name string
+		log  func(l *Logger)
+		want []string // output of three calls with rate limit of one message and of three with deduplication
+	}{
+		{"ErrorDetail", func(l *Logger) { l.ErrorDetail("failed", "trace") },
+			[]string{"[ERROR] failed", "trace"}},
+		{"LogAt", func(l *Logger) { l.LogAt(past, LOG_ERROR, "replayed") }, []string{"[ERROR] replayed"}},
+		{"Banner", func(l *Logger) { l.Banner(LOG_ERROR, map[string]string{"version": "1.0"}) },
+			[]string{"[ERROR] " + strings.Repeat("=", bannerWidth), "[ERROR] version : 1.0",
+				"[ERROR] " + strings.Repeat("=", bannerWidth)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" rate limited", func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithRateLimit(LOG_ERROR, 1))
+			for i := 0; i < 3; i++ {
+				tt.log(l)
+			}
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+		t.Run(tt.name+" deduplicated", func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithDedup(time.Minute))
+			for i := 0; i < 3; i++ {
+				tt.log(l)
+			}
+			l.Flush()
+			want := append(tt.want[:len(tt.want):len(tt.want)], "[ERROR] (repeated 2x)")
+			if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+				t.Errorf("output %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package twigsnake
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// registry holds loggers created with NewRegistered.
+var registry = struct {
+	sync.Mutex
+	loggers map[string]*Logger
+}{loggers: make(map[string]*Logger)}
+
+// NewRegistered creates new Logger instance the same way New does and registers it under given name, so that it can be
+// found with Loggers, e.g. by an admin endpoint adjusting levels of all loggers at once. The name is also stored as
+// "name" metadata (see Meta). Error is returned if the name is already taken.
+func NewRegistered(name string, lvl int, dest io.Writer) (*Logger, error) {
+	l, err := New(lvl, dest)
+	if err != nil {
+		return nil, err
+	}
+	l.SetMeta("name", name)
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, dup := registry.loggers[name]; dup {
+		return nil, fmt.Errorf("logger %q is already registered", name)
+	}
+	registry.loggers[name] = l
+	return l, nil
+}
+
+// Unregister removes logger registered under given name from the registry.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.loggers, name)
+}
+
+// Loggers returns all loggers created with NewRegistered by their names. The returned map is a copy, so it can be
+// modified freely.
+func Loggers() map[string]*Logger {
+	registry.Lock()
+	defer registry.Unlock()
+	loggers := make(map[string]*Logger, len(registry.loggers))
+	for name, l := range registry.loggers {
+		loggers[name] = l
+	}
+	return loggers
+}
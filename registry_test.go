@@ -0,0 +1,70 @@
+package twigsnake
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	const a, b = "test-registry-a", "test-registry-b"
+	defer Unregister(a)
+	defer Unregister(b)
+
+	var buf bytes.Buffer
+	la, err := NewRegistered(a, LOG_INFO, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb, err := NewRegistered(b, LOG_DEBUG, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := la.Meta("name"); !ok || name != a {
+		t.Errorf("Meta(name) = %v, %v; want %q", name, ok, a)
+	}
+
+	loggers := Loggers()
+	if loggers[a] != la || loggers[b] != lb {
+		t.Fatalf("Loggers() = %v, want %s and %s registered", loggers, a, b)
+	}
+	delete(loggers, a) // the map is a copy
+	if Loggers()[a] != la {
+		t.Error("deleting from returned map unregistered logger")
+	}
+
+	// Registered loggers work as usual, e.g. for an endpoint adjusting levels of all of them.
+	for _, l := range Loggers() {
+		if l == la || l == lb {
+			l.SetLogLevel(LOG_WARN)
+		}
+	}
+	la.Info("hidden")
+	lb.Warn("shown")
+	if got := buf.String(); !strings.HasSuffix(got, "[WARN] shown\n") || strings.Contains(got, "hidden") {
+		t.Errorf("output %q, want only the warning", got)
+	}
+
+	if l, err := NewRegistered(a, LOG_DEBUG, &buf); err == nil || l != nil {
+		t.Errorf("duplicate NewRegistered = %v, %v; want error", l, err)
+	} else if !strings.Contains(err.Error(), a) {
+		t.Errorf("error %q doesn't name the logger", err)
+	}
+	if Loggers()[a] != la {
+		t.Error("duplicate registration replaced the original logger")
+	}
+	if l, err := NewRegistered("test-registry-invalid", 100, &buf); err == nil || l != nil {
+		t.Errorf("NewRegistered with invalid level = %v, %v; want error", l, err)
+	}
+	if _, ok := Loggers()["test-registry-invalid"]; ok {
+		t.Error("logger with invalid level registered")
+	}
+
+	Unregister(a)
+	if _, ok := Loggers()[a]; ok {
+		t.Errorf("%s still registered after Unregister", a)
+	}
+	if _, err := NewRegistered(a, LOG_INFO, &buf); err != nil {
+		t.Errorf("registering unregistered name again: %v", err)
+	}
+}
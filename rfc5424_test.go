@@ -0,0 +1,142 @@
+package twigsnake
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc5424Frame is a parsed RFC 5424 syslog message.
+type rfc5424Frame struct {
+	pri                        int
+	version                    string
+	timestamp                  time.Time
+	hostname, appName, procID  string
+	msgID, structuredData, msg string
+}
+
+// parseRFC5424 parses syslog message produced by rfc5424Formatter.
+func parseRFC5424(line string) (rfc5424Frame, error) {
+	var f rfc5424Frame
+	end := strings.IndexByte(line, '>')
+	if !strings.HasPrefix(line, "<") || end < 2 || end > 4 {
+		return f, errors.New("malformed PRI")
+	}
+	pri, err := strconv.Atoi(line[1:end])
+	if err != nil || pri > 191 {
+		return f, errors.New("invalid PRI " + line[1:end])
+	}
+	f.pri = pri
+
+	header := strings.SplitN(line[end+1:], " ", 7)
+	if len(header) != 7 {
+		return f, errors.New("truncated header")
+	}
+	f.version, f.hostname, f.appName, f.procID, f.msgID = header[0], header[2], header[3], header[4], header[5]
+	if f.timestamp, err = time.Parse(time.RFC3339Nano, header[1]); err != nil {
+		return f, err
+	}
+
+	rest := header[6]
+	if strings.HasPrefix(rest, "-") {
+		f.structuredData, rest = "-", rest[1:]
+	} else {
+		i := 0
+		for ; i < len(rest) && rest[i] != ']'; i++ {
+			if rest[i] == '\\' {
+				i++
+			}
+		}
+		if !strings.HasPrefix(rest, "[") || i >= len(rest) {
+			return f, errors.New("malformed structured data")
+		}
+		f.structuredData, rest = rest[:i+1], rest[i+1:]
+	}
+	if rest != "" && rest[0] != ' ' {
+		return f, errors.New("missing space before message")
+	}
+	f.msg = strings.TrimPrefix(rest, " ")
+	return f, nil
+}
+
+func TestRFC5424Priority(t *testing.T) {
+	tests := []struct {
+		facility int
+		log      func(l *Logger)
+		wantPRI  int
+	}{
+		{0, func(l *Logger) { l.Emerg("m") }, 0},
+		{0, func(l *Logger) { l.Info("m") }, 6},
+		{1, func(l *Logger) { l.Error("m") }, 11},
+		{4, func(l *Logger) { l.Warn("m") }, 36},
+		{16, func(l *Logger) { l.Notice("m") }, 133},
+		{23, func(l *Logger) { l.Debug("m") }, 191},
+		{3, func(l *Logger) { l.Audit("m") }, 109},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.wantPRI), func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.SetFormatter(NewRFC5424Formatter("host", "app"))
+			if err := l.SetFacility(tt.facility); err != nil {
+				t.Fatal(err)
+			}
+			tt.log(l)
+			f, err := parseRFC5424(strings.TrimSuffix(buf.String(), "\n"))
+			if err != nil {
+				t.Fatalf("can't parse %q: %v", buf.String(), err)
+			}
+			if f.pri != tt.wantPRI {
+				t.Errorf("PRI %d, want %d", f.pri, tt.wantPRI)
+			}
+		})
+	}
+}
+
+func TestRFC5424Format(t *testing.T) {
+	tests := []struct {
+		name                string
+		hostname, appName   string
+		log                 func(l *Logger)
+		wantHost, wantApp   string
+		wantSD, wantMessage string
+	}{
+		{"no fields", "host", "app", func(l *Logger) { l.Info("hello world") }, "host", "app", "-", "hello world"},
+		{"fields", "host", "app", func(l *Logger) { l.Infow("m", "user", "ann", "n", 1) }, "host", "app",
+			`[fields@32473 user="ann" n="1"]`, "m"},
+		{"escaped field", "host", "app", func(l *Logger) { l.Infow("m", "k=]", `a"b]c\`) }, "host", "app",
+			`[fields@32473 k__="a\"b\]c\\"]`, "m"},
+		{"no message", "host", "app", func(l *Logger) { l.Infow("", "k", "v") }, "host", "app",
+			`[fields@32473 k="v"]`, ""},
+		{"unprintable header", "my host", "app\tname", func(l *Logger) { l.Info("m") }, "my_host", "app_name", "-", "m"},
+		{"defaults", "", "", func(l *Logger) { l.Info("m") }, defaultHostname(t), "twigsnake.test", "-", "m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.SetFormatter(NewRFC5424Formatter(tt.hostname, tt.appName))
+			tt.log(l)
+			f, err := parseRFC5424(strings.TrimSuffix(buf.String(), "\n"))
+			if err != nil {
+				t.Fatalf("can't parse %q: %v", buf.String(), err)
+			}
+			want := rfc5424Frame{pri: LOG_INFO, version: "1", timestamp: testTime, hostname: tt.wantHost,
+				appName: tt.wantApp, procID: strconv.Itoa(os.Getpid()), msgID: "-", structuredData: tt.wantSD,
+				msg: tt.wantMessage}
+			if f != want {
+				t.Errorf("parsed %+v\nwant   %+v", f, want)
+			}
+		})
+	}
+}
+
+// defaultHostname returns hostname NewRFC5424Formatter defaults to.
+func defaultHostname(t *testing.T) string {
+	h, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+	return rfc5424Header(h, 255)
+}
@@ -0,0 +1,117 @@
+package twigsnake
+
+import (
+	"context"
+	"strings"
+)
+
+// minTailBuffer is the minimal capacity of channels returned by Tail.
+const minTailBuffer = 64
+
+// ringBuffer keeps fixed number of most recent lines.
+type ringBuffer struct {
+	lines []string
+	start int
+	n     int
+}
+
+func (r *ringBuffer) push(s string) {
+	if len(r.lines) == 0 {
+		return
+	}
+	if r.n < len(r.lines) {
+		r.lines[(r.start+r.n)%len(r.lines)] = s
+		r.n++
+		return
+	}
+	r.lines[r.start] = s
+	r.start = (r.start + 1) % len(r.lines)
+}
+
+// snapshot returns buffered lines, oldest first.
+func (r *ringBuffer) snapshot() []string {
+	out := make([]string, r.n)
+	for i := range out {
+		out[i] = r.lines[(r.start+i)%len(r.lines)]
+	}
+	return out
+}
+
+// SetRingBuffer makes logger keep last size emitted lines in memory, so they can be retrieved with Recent or replayed by
+// Tail. Lines are stored exactly as they were written to the output, without trailing newline. Changing the size
+// discards already buffered lines; zero size disables buffering.
+func (l *Logger) SetRingBuffer(size int) {
+	if size < 0 {
+		size = 0
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.recent = ringBuffer{lines: make([]string, size)}
+}
+
+// Recent returns lines collected by the ring buffer, oldest first. Named loggers and loggers returned by With share the
+// ring buffer of the logger they belong to.
+func (l *Logger) Recent() []string {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recent.snapshot()
+}
+
+// Tail returns a channel which first receives all lines collected by the ring buffer and then every newly emitted line
+// until ctx is cancelled, when the channel gets closed. Consumer which can't keep up loses the oldest pending lines
+// rather than blocking the logger.
+func (l *Logger) Tail(ctx context.Context) <-chan string {
+	r := l.base()
+	r.mu.Lock()
+	size := len(r.recent.lines)
+	if size < minTailBuffer {
+		size = minTailBuffer
+	}
+	ch := make(chan string, size)
+	for _, s := range r.recent.snapshot() {
+		ch <- s
+	}
+	if r.tails == nil {
+		r.tails = make(map[chan string]struct{})
+	}
+	r.tails[ch] = struct{}{}
+	r.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		r.mu.Lock()
+		delete(r.tails, ch)
+		close(ch)
+		r.mu.Unlock()
+	}()
+	return ch
+}
+
+// record stores emitted line in the ring buffer and passes it to tail subscribers. Must be called with l.mu held.
+func (l *Logger) record(line []byte) {
+	if len(l.recent.lines) == 0 && len(l.tails) == 0 {
+		return
+	}
+
+	s := strings.TrimSuffix(string(line), l.lineEnding)
+	l.recent.push(s)
+	for ch := range l.tails {
+		select {
+		case ch <- s:
+			continue
+		default:
+		}
+		// Subscriber is lagging: make room by dropping the oldest pending line.
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- s:
+		default:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package twigsnake
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecent(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		n    int
+		want []string
+	}{
+		{"disabled", 0, 3, nil},
+		{"partially filled", 3, 2, []string{"[INFO] 0", "[INFO] 1"}},
+		{"wrapped", 3, 5, []string{"[INFO] 2", "[INFO] 3", "[INFO] 4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestLogger(t, LOG_DEBUG)
+			c := l.Named("x").With("k", "v")
+			c.SetRingBuffer(tt.size)
+			for i := 0; i < tt.n; i++ {
+				l.Info(i)
+			}
+			for _, got := range [][]string{l.Recent(), c.Recent()} {
+				if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+					t.Errorf("Recent() = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTail(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetRingBuffer(2)
+	l.Info("old")
+	c := l.Named("db").With("k", "v")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := c.Tail(ctx)
+	l.Info("root")
+	c.Info("child")
+
+	want := []string{"[INFO] old", "[INFO] root", "[INFO] child logger=db k=v"}
+	for _, w := range want {
+		select {
+		case got := <-ch:
+			if got != w {
+				t.Errorf("received %q, want %q", got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", w)
+		}
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("received line after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after cancellation")
+	}
+}
@@ -0,0 +1,121 @@
+package twigsnake
+
+import (
+	"os"
+	"strconv"
+	"sync"
+)
+
+// RotatingFileWriter is io.Writer appending to a file which is rotated when it grows too big: app.log becomes
+// app.log.1, app.log.1 becomes app.log.2 and so on, up to the configured number of backups, while writing continues
+// to a fresh app.log. It is safe for concurrent use, so one writer can be shared by all severity levels.
+type RotatingFileWriter struct {
+	mu         sync.Mutex
+	path       string
+	maxBytes   int64
+	maxBackups int
+	f          *os.File
+	size       int64
+}
+
+// NewRotatingFileWriter opens (creating if necessary) file at path for appending and returns writer rotating it once
+// writing next message would make it exceed maxBytes. Up to maxBackups rotated files are kept; with zero maxBackups the
+// file is simply truncated. Non-positive maxBytes disables rotation. Messages are never split between files, so a
+// single message bigger than maxBytes gets a file of its own. If rotation fails, e.g. because a backup can't be renamed,
+// the message is still written to the current file and the error is returned along with it.
+func NewRotatingFileWriter(path string, maxBytes int64, maxBackups int) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the log file for appending. Must be called with w.mu held.
+func (w *RotatingFileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = fi.Size()
+	return nil
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	var rotateErr error
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		rotateErr = w.rotate()
+		if w.f == nil {
+			return 0, rotateErr
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// rotate closes the current file, shifts backups and opens a fresh file. If shifting fails, the current file is
+// reopened, so writing continues to it and rotation is retried with the next write. Must be called with w.mu held.
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	w.f = nil
+
+	err := w.shift()
+	if openErr := w.open(); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// shift moves the current file to the first backup, shifting older backups and dropping the oldest one, or removes
+// the current file if no backups are kept.
+func (w *RotatingFileWriter) shift() error {
+	if w.maxBackups > 0 {
+		os.Remove(w.backup(w.maxBackups))
+		for i := w.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(w.backup(i), w.backup(i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(w.path, w.backup(1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// backup returns path of i-th backup.
+func (w *RotatingFileWriter) backup(i int) string {
+	return w.path + "." + strconv.Itoa(i)
+}
+
+// Close closes the current file. Writes after Close fail.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
@@ -0,0 +1,111 @@
+package twigsnake
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRotatingFileWriter(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string // content of app.log before the writer is opened
+		maxBytes   int64
+		maxBackups int
+		writes     []string
+		want       map[string]string
+	}{
+		{"below threshold", "", 10, 2, []string{"aaa\n", "bbb\n"}, map[string]string{"app.log": "aaa\nbbb\n"}},
+		{"rotation", "", 8, 2, []string{"aaa\n", "bbb\n", "ccc\n"},
+			map[string]string{"app.log": "ccc\n", "app.log.1": "aaa\nbbb\n"}},
+		{"backups capped", "", 4, 2, []string{"a1\n", "a2\n", "a3\n", "a4\n"},
+			map[string]string{"app.log": "a4\n", "app.log.1": "a3\n", "app.log.2": "a2\n"}},
+		{"no backups", "", 4, 0, []string{"a1\n", "a2\n"}, map[string]string{"app.log": "a2\n"}},
+		{"oversized message", "", 4, 3, []string{"a\n", "long line\n", "b\n"},
+			map[string]string{"app.log": "b\n", "app.log.1": "long line\n", "app.log.2": "a\n"}},
+		{"existing file counts", "old\n", 6, 1, []string{"new\n"},
+			map[string]string{"app.log": "new\n", "app.log.1": "old\n"}},
+		{"rotation disabled", "", 0, 1, []string{"aaa\n", "bbb\n"}, map[string]string{"app.log": "aaa\nbbb\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "app.log")
+			if tt.existing != "" {
+				if err := ioutil.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w, err := NewRotatingFileWriter(path, tt.maxBytes, tt.maxBackups)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+			for _, s := range tt.writes {
+				if n, err := w.Write([]byte(s)); n != len(s) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", s, n, err)
+				}
+			}
+			if got := readDir(t, dir); !equalFiles(got, tt.want) {
+				t.Errorf("files %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotatingFileWriterConcurrent(t *testing.T) {
+	const goroutines, messages, line = 4, 50, "0123456789\n"
+	dir := t.TempDir()
+	w, err := NewRotatingFileWriter(filepath.Join(dir, "app.log"), 100, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	l, _ := newTestLogger(t, LOG_DEBUG, WithFlags(0), WithPrefixes(map[int]string{LOG_INFO: ""}))
+	l.SetOutput(w)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < messages; i++ {
+				l.Info(strings.TrimSuffix(line, "\n"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for name, content := range readDir(t, dir) {
+		if len(content) > 100 {
+			t.Errorf("%s has %d bytes, more than the limit", name, len(content))
+		}
+		if strings.Replace(content, line, "", -1) != "" {
+			t.Errorf("%s has torn lines: %q", name, content)
+		}
+		total += strings.Count(content, line)
+	}
+	if total != goroutines*messages {
+		t.Errorf("%d lines written, want %d", total, goroutines*messages)
+	}
+}
+
+func TestRotatingFileWriterClosed(t *testing.T) {
+	w, err := NewRotatingFileWriter(filepath.Join(t.TempDir(), "app.log"), 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	if _, err := w.Write([]byte("x\n")); err != os.ErrClosed {
+		t.Errorf("Write after Close: %v, want %v", err, os.ErrClosed)
+	}
+}
@@ -0,0 +1,73 @@
+package twigsnake
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// FlushOnSignals makes logger flush (see Flush) when the process receives one of listed signals, os.Interrupt and
+// SIGTERM by default, so the last lines logged before shutdown aren't lost in buffers or the asynchronous queue. After
+// flushing, the signal is raised again with the handler removed, so the process terminates the way it would without it;
+// if the signal can't be re-raised, the process exits with status 1. SetSignalExit makes it exit with given status
+// instead of re-raising. Returned function removes the handler.
+func (l *Logger) FlushOnSignals(sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = defaultFlushSignals
+	}
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		select {
+		case sig := <-ch:
+			l.Flush()
+			signal.Stop(ch)
+			if code := l.signalExitCode(); code >= 0 {
+				osExit(code)
+				return
+			}
+			if p, err := os.FindProcess(os.Getpid()); err != nil || p.Signal(sig) != nil {
+				osExit(1)
+			}
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// SetSignalExit makes FlushOnSignals exit the process with given status after flushing, rather than re-raising the
+// signal. This suits programs which report shutdown through exit status, e.g. 130 for interrupt as shells do. Negative
+// code restores the default of re-raising the signal.
+func (l *Logger) SetSignalExit(code int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if code < 0 {
+		code = -1
+	}
+	r.signalExit = code
+}
+
+// WithSignalExit makes FlushOnSignals exit with given status instead of re-raising the signal (see SetSignalExit).
+func WithSignalExit(code int) Option {
+	return func(l *Logger) {
+		l.SetSignalExit(code)
+	}
+}
+
+// signalExitCode returns exit status set with SetSignalExit, negative if the signal is re-raised.
+func (l *Logger) signalExitCode() int {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.signalExit
+}
@@ -0,0 +1,12 @@
+//go:build !plan9
+// +build !plan9
+
+package twigsnake
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultFlushSignals are the signals FlushOnSignals handles when none are given.
+var defaultFlushSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
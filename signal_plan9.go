@@ -0,0 +1,6 @@
+package twigsnake
+
+import "os"
+
+// defaultFlushSignals are the signals FlushOnSignals handles when none are given.
+var defaultFlushSignals = []os.Signal{os.Interrupt}
@@ -0,0 +1,109 @@
+//go:build !plan9 && !windows
+// +build !plan9,!windows
+
+package twigsnake
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flushNotifier is a writer reporting calls of Flush on a channel.
+type flushNotifier chan struct{}
+
+func (f flushNotifier) Write(p []byte) (int, error) { return len(p), nil }
+func (f flushNotifier) Flush() error                { f <- struct{}{}; return nil }
+
+func TestFlushOnSignals(t *testing.T) {
+	defer func(exit func(int)) { osExit = exit }(osExit)
+	exits := make(chan int, 1)
+	osExit = func(c int) { exits <- c }
+
+	tests := []struct {
+		name     string
+		exit     int
+		wantExit int  // expected exit status, negative if none
+		reraised bool // whether the signal is raised again
+	}{
+		{"re-raise by default", -1, -1, true},
+		{"exit with status", 130, 130, false},
+		{"exit with zero status", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Signals are also delivered here, so that the re-raised one doesn't terminate the test.
+			seen := make(chan os.Signal, 2)
+			signal.Notify(seen, syscall.SIGUSR1)
+			defer signal.Stop(seen)
+
+			flushed := make(flushNotifier, 1)
+			l, _ := newTestLogger(t, LOG_DEBUG, WithSignalExit(tt.exit))
+			l.SetOutput(flushed)
+			stop := l.FlushOnSignals(syscall.SIGUSR1)
+			defer stop()
+
+			if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-flushed:
+			case <-time.After(5 * time.Second):
+				t.Fatal("logger not flushed on signal")
+			}
+
+			want := 1
+			if tt.reraised {
+				want = 2
+			}
+			for i := 0; i < want; i++ {
+				select {
+				case <-seen:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("signal seen %d times, want %d", i, want)
+				}
+			}
+
+			select {
+			case code := <-exits:
+				if code != tt.wantExit {
+					t.Errorf("exit status %d, want %d", code, tt.wantExit)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantExit >= 0 {
+					t.Errorf("process didn't exit, want status %d", tt.wantExit)
+				}
+			}
+			select {
+			case <-seen:
+				t.Error("signal raised again after exit")
+			default:
+			}
+		})
+	}
+}
+
+func TestFlushOnSignalsStop(t *testing.T) {
+	seen := make(chan os.Signal, 1)
+	signal.Notify(seen, syscall.SIGUSR1)
+	defer signal.Stop(seen)
+
+	flushed := make(flushNotifier, 1)
+	l, _ := newTestLogger(t, LOG_DEBUG)
+	l.SetOutput(flushed)
+	stop := l.FlushOnSignals(syscall.SIGUSR1)
+	stop()
+	stop()
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	<-seen
+	select {
+	case <-flushed:
+		t.Error("logger flushed after the handler was removed")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,66 @@
+package twigsnake
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// Sink is a destination with its own format and severity threshold: it receives messages of level MinLevel and more
+// severe, formatted with Formatter (FormatText when nil).
+type Sink struct {
+	Formatter
+	io.Writer
+	MinLevel int
+}
+
+// SetSinks replaces regular per-level outputs with given sinks, so that e.g. text goes to the console, JSON to a file
+// and only errors to a network collector, all configured in one place. Every message is formatted and written
+// separately for each sink whose threshold it passes, while logging level of the logger still applies to all of them.
+// Alert sink and ring buffer keep receiving lines in the logger's own format. Calling SetSinks without arguments
+// restores regular outputs.
+func (l *Logger) SetSinks(sinks ...Sink) {
+	var ss []Sink
+	for _, s := range sinks {
+		if s.Writer == nil {
+			continue
+		}
+		if s.Formatter == nil {
+			s.Formatter = textFormatter{}
+		}
+		ss = append(ss, s)
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sinks = ss
+}
+
+// NDJSONExport attaches w as additional destination receiving every printed message as newline-delimited JSON (see
+// FormatJSON), regardless of sinks and regular outputs, which keep working as before. This is the format bulk log
+// ingestion tools expect. With gzipped set, the stream is gzip-compressed; Close finalizes it (without closing w), while
+// Flush flushes compressed data written so far.
+func (l *Logger) NDJSONExport(w io.Writer, gzipped bool) {
+	if gzipped {
+		w = gzip.NewWriter(w)
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exports = append(r.exports, Sink{Formatter: jsonFormatter{}, Writer: w, MinLevel: LOG_DEBUG})
+}
+
+// emitSinks formats entry e for every sink accepting its level and writes it there. Must be called with l.mu held.
+func (l *Logger) emitSinks(e *Entry, sinks []Sink) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	for _, s := range sinks {
+		if e.Level > s.MinLevel {
+			continue
+		}
+		*buf = l.formatLine((*buf)[:0], s.Formatter, e)
+		l.write(s.Formatter, s.Writer, e.Level, *buf)
+	}
+}
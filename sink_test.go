@@ -0,0 +1,122 @@
+package twigsnake
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// decodeNDJSON decodes newline-delimited JSON objects read from r, failing the test on lines which aren't valid JSON.
+func decodeNDJSON(t *testing.T, r io.Reader) []map[string]interface{} {
+	t.Helper()
+	var objs []map[string]interface{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &obj); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		objs = append(objs, obj)
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return objs
+}
+
+func TestNDJSONExport(t *testing.T) {
+	ts := testTime.Format(time.RFC3339)
+	want := []map[string]interface{}{
+		{"time": ts, "level": "info", "msg": "started", "port": 8080.0},
+		{"time": ts, "level": "error", "msg": "line\nbreak", "logger": "db"},
+	}
+	for _, gzipped := range []bool{false, true} {
+		name := "plain"
+		if gzipped {
+			name = "gzipped"
+		}
+		t.Run(name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			var export bytes.Buffer
+			l.NDJSONExport(&export, gzipped)
+
+			l.Infow("started", "port", 8080)
+			l.Debug("hidden")
+			l.Named("db").Error("line\nbreak")
+			if err := l.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Regular output keeps working.
+			if got, want := buf.String(), "[INFO] started port=8080\n[ERROR] line\nbreak logger=db\n"; got != want {
+				t.Errorf("output %q, want %q", got, want)
+			}
+			r := io.Reader(&export)
+			if gzipped {
+				zr, err := gzip.NewReader(&export)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer zr.Close()
+				r = zr
+			}
+			if got := decodeNDJSON(t, r); !reflect.DeepEqual(got, want) {
+				t.Errorf("export %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetSinks(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_INFO)
+	var console, file, errs bytes.Buffer
+	l.SetSinks(
+		Sink{Writer: &console, MinLevel: LOG_DEBUG},
+		Sink{Formatter: FormatLogfmt.Formatter(), Writer: &file, MinLevel: LOG_NOTICE},
+		Sink{Formatter: FormatJSON.Formatter(), Writer: &errs, MinLevel: LOG_ERROR},
+		Sink{MinLevel: LOG_DEBUG}, // without writer, ignored
+	)
+
+	l.Info("i")
+	l.Warn("w")
+	l.Crit("c")
+	l.Debug("hidden") // below the logger's level, so no sink gets it
+	if buf.Len() != 0 {
+		t.Errorf("regular output %q, want none", buf.String())
+	}
+	if got, want := console.String(), "[INFO] i\n[WARN] w\n[CRIT] c\n"; got != want {
+		t.Errorf("console %q, want %q", got, want)
+	}
+	fileWant := "time=2021-03-05T14:30:15Z level=warn msg=w\ntime=2021-03-05T14:30:15Z level=crit msg=c\n"
+	if got := file.String(); got != fileWant {
+		t.Errorf("file %q, want %q", got, fileWant)
+	}
+	if got, want := errs.String(), `{"time":"2021-03-05T14:30:15Z","level":"crit","msg":"c"}`+"\n"; got != want {
+		t.Errorf("errors %q, want %q", got, want)
+	}
+
+	// Replacing sinks stops writing to the old ones.
+	console.Reset()
+	var replaced bytes.Buffer
+	l.SetSinks(Sink{Writer: &replaced, MinLevel: LOG_WARN})
+	l.Info("i")
+	l.Error("e")
+	if got, want := replaced.String(), "[ERROR] e\n"; got != want {
+		t.Errorf("replaced sink %q, want %q", got, want)
+	}
+	if console.Len() != 0 || file.String() != fileWant {
+		t.Errorf("old sinks written after replacement: console %q, file %q", console.String(), file.String())
+	}
+
+	// No sinks restore regular outputs.
+	l.SetSinks()
+	l.Info("back")
+	if got, want := buf.String(), "[INFO] back\n"; got != want {
+		t.Errorf("regular output %q, want %q", got, want)
+	}
+}
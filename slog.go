@@ -0,0 +1,116 @@
+//go:build go1.21
+// +build go1.21
+
+package twigsnake
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogHandler is slog.Handler backed by Logger.
+type slogHandler struct {
+	l      *Logger
+	attrs  []Field // fields added with WithAttrs, keys already qualified with groups
+	prefix string  // group prefix for keys of subsequent attributes, e.g. "request."
+}
+
+// SlogHandler returns slog.Handler sending records to l, so that twigsnake can back slog.Logger. Record levels are mapped
+// to the nearest severity: slog.LevelDebug to LOG_DEBUG, slog.LevelInfo to LOG_INFO, slog.LevelInfo+2 to LOG_NOTICE,
+// slog.LevelWarn to LOG_WARN, slog.LevelError to LOG_ERROR and every four levels above it to LOG_CRIT, LOG_ALERT and
+// LOG_EMERG respectively. Attributes become structured fields; keys of grouped attributes are qualified with group names
+// joined by dots. Enabled consults current logging level and context sampler of l (see ContextEnabled); Handle drops
+// records below the logging level as well, in case it is called directly. Messages are stamped with record time, unless
+// it is zero.
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{l: l}
+}
+
+// InstallAsSlogDefault makes l the backend of slog's default logger, so top-level slog.Info and friends are routed
+// through twigsnake. Note that slog.SetDefault also redirects output of the standard log package's default logger to
+// slog, and thus to l.
+func (l *Logger) InstallAsSlogDefault() {
+	slog.SetDefault(slog.New(l.SlogHandler()))
+}
+
+// levelFromSlog maps slog level to twigsnake severity level.
+func levelFromSlog(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return LOG_DEBUG
+	case level < slog.LevelInfo+2:
+		return LOG_INFO
+	case level < slog.LevelWarn:
+		return LOG_NOTICE
+	case level < slog.LevelError:
+		return LOG_WARN
+	case level < slog.LevelError+4:
+		return LOG_ERROR
+	case level < slog.LevelError+8:
+		return LOG_CRIT
+	case level < slog.LevelError+12:
+		return LOG_ALERT
+	}
+	return LOG_EMERG
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.l.ContextEnabled(ctx, levelFromSlog(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	lvl := levelFromSlog(r.Level)
+	if !h.l.EffectiveEnabled(lvl) {
+		return nil
+	}
+	ctxFields := ContextFields(ctx)
+	fields := make([]Field, 0, len(ctxFields)+len(h.attrs)+r.NumAttrs())
+	fields = append(fields, ctxFields...)
+	fields = append(fields, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendSlogAttr(fields, h.prefix, a)
+		return true
+	})
+	h.l.outputAt(lvl, r.Message, fields, r.PC, r.Time)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append([]Field(nil), h.attrs...)
+	for _, a := range attrs {
+		h2.attrs = appendSlogAttr(h2.attrs, h.prefix, a)
+	}
+	return &h2
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// appendSlogAttr converts slog attribute to fields, flattening groups, and appends them to fields. Keys are qualified
+// with prefix. Empty attributes are skipped, as slog.Handler contract requires.
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	if a.Key == "" && v.Any() == nil {
+		return fields
+	}
+	return append(fields, Field{prefix + a.Key, v.Any()})
+}
@@ -0,0 +1,137 @@
+//go:build go1.21
+// +build go1.21
+
+package twigsnake
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlogLevels(t *testing.T) {
+	tests := []struct {
+		level   slog.Level
+		want    string
+		enabled bool
+	}{
+		{slog.LevelDebug - 4, "", false},
+		{slog.LevelDebug, "", false},
+		{slog.LevelInfo, "[INFO] m", true},
+		{slog.LevelInfo + 2, "[NOTICE] m", true},
+		{slog.LevelWarn, "[WARN] m", true},
+		{slog.LevelError, "[ERROR] m", true},
+		{slog.LevelError + 4, "[CRIT] m", true},
+		{slog.LevelError + 8, "[ALERT] m", true},
+		{slog.LevelError + 12, "[EMERG] m", true},
+		{slog.LevelError + 100, "[EMERG] m", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			h := l.SlogHandler()
+			if got := h.Enabled(context.Background(), tt.level); got != tt.enabled {
+				t.Errorf("Enabled = %v, want %v", got, tt.enabled)
+			}
+			// Handle is called directly, bypassing Enabled, to check it drops disabled records by itself.
+			if err := h.Handle(context.Background(), slog.NewRecord(time.Time{}, tt.level, "m", 0)); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(lines(buf), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogEnabledFollowsLogLevel(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_INFO)
+	logger := slog.New(l.SlogHandler())
+	ctx := context.Background()
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		t.Fatal("debug enabled on LOG_INFO")
+	}
+	l.SetLogLevel(LOG_DEBUG)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		t.Error("debug disabled after SetLogLevel(LOG_DEBUG)")
+	}
+	l.SetLogLevel(LOG_ERROR)
+	if logger.Enabled(ctx, slog.LevelWarn) {
+		t.Error("warn enabled after SetLogLevel(LOG_ERROR)")
+	}
+}
+
+func TestSlogTime(t *testing.T) {
+	recordTime := time.Date(2022, 7, 1, 8, 9, 10, 0, time.UTC)
+	tests := []struct {
+		name string
+		time time.Time
+		want string
+	}{
+		{"record time", recordTime, "2022/07/01 08:09:10 [INFO] m"},
+		{"zero time uses clock", time.Time{}, "2021/03/05 14:30:15 [INFO] m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO, WithFlags(log.Ldate|log.Ltime|log.LUTC|log.Lmsgprefix))
+			if err := l.SlogHandler().Handle(context.Background(), slog.NewRecord(tt.time, slog.LevelInfo, "m", 0)); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(lines(buf), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlogAttrs(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+		want string
+	}{
+		{"attributes", func(s *slog.Logger) { s.Info("m", "user", "ann", slog.Int("id", 7)) }, "[INFO] m user=ann id=7"},
+		{"WithAttrs", func(s *slog.Logger) { s.With("req", 1).Warn("m", "k", "v") }, "[WARN] m req=1 k=v"},
+		{"WithGroup", func(s *slog.Logger) { s.WithGroup("http").With("method", "GET").Info("m", "status", 200) },
+			"[INFO] m http.method=GET http.status=200"},
+		{"group attribute", func(s *slog.Logger) { s.Info("m", slog.Group("db", "rows", 3)) }, "[INFO] m db.rows=3"},
+		{"empty attribute skipped", func(s *slog.Logger) { s.Info("m", slog.Attr{}, "k", "v") }, "[INFO] m k=v"},
+		{"empty group ignored", func(s *slog.Logger) { s.WithGroup("").Info("m", "k", "v") }, "[INFO] m k=v"},
+		{"context fields", func(s *slog.Logger) {
+			s.InfoContext(ContextWithFields(context.Background(), Field{"trace", "abc"}), "m", "k", "v")
+		}, "[INFO] m trace=abc k=v"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			tt.log(slog.New(l.SlogHandler()))
+			if got := strings.Join(lines(buf), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallAsSlogDefault(t *testing.T) {
+	prev, prevOutput, prevFlags := slog.Default(), log.Writer(), log.Flags()
+	defer func() {
+		slog.SetDefault(prev)
+		log.SetOutput(prevOutput)
+		log.SetFlags(prevFlags)
+	}()
+
+	l, buf := newTestLogger(t, LOG_INFO)
+	l.InstallAsSlogDefault()
+	slog.Info("hi", "k", 1)
+	slog.Debug("hidden")
+	slog.Error("failed", slog.Group("req", "id", 7))
+	log.Print("standard")
+
+	want := []string{"[INFO] hi k=1", "[ERROR] failed req.id=7", "[INFO] standard"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
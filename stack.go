@@ -0,0 +1,102 @@
+package twigsnake
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// DefaultStackDepth is the default maximum number of frames captured by ErrorStack.
+const DefaultStackDepth = 32
+
+// Frame is a single frame of captured call stack.
+type Frame struct {
+	Func string // fully qualified function name, e.g. "github.com/user/pkg.(*T).Method"
+	File string // full path of the source file
+	Line int
+}
+
+// Stack is captured call stack, innermost frame first. As structured field value it is rendered structurally, so stack
+// traces stay queryable in log stores: JSON formats render it as array of {"func","file","line"} objects and the text
+// format prints it after the message, one frame per line, the same way Go prints stacks of panicking goroutines. Other
+// formats use its String representation.
+type Stack []Frame
+
+// String returns stack as a single line of frames in "func (file:line)" form, separated with ", ".
+func (s Stack) String() string {
+	var buf []byte
+	for i, f := range s {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, f.Func...)
+		buf = append(buf, " ("...)
+		buf = append(buf, f.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(f.Line), 10)
+		buf = append(buf, ')')
+	}
+	return string(buf)
+}
+
+// SetStackDepth limits the number of frames captured by ErrorStack, DefaultStackDepth by default. Outermost frames beyond
+// the limit are dropped. Non-positive depth disables capturing, so ErrorStack behaves like Error.
+func (l *Logger) SetStackDepth(depth int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stackDepth = depth
+}
+
+// ErrorStack prints error message with call stack of its caller attached as "stack" field (see Stack). Message will
+// appear on logging level twigsnake.LOG_ERROR and higher. Handles arguments in the same manner as log.Print. The stack is
+// captured only if the message is going to be printed.
+func (l *Logger) ErrorStack(v ...interface{}) {
+	if l.EffectiveEnabled(LOG_ERROR) {
+		v, fields := l.trailingFields(v)
+		r := l.base()
+		r.mu.Lock()
+		depth := r.stackDepth
+		r.mu.Unlock()
+		if depth > 0 {
+			fields = append(fields[:len(fields):len(fields)], Field{"stack", captureStack(1, depth)})
+		}
+		l.output(LOG_ERROR, fmt.Sprint(v...), fields)
+	}
+}
+
+// captureStack returns up to depth frames of the call stack, starting with the function skip frames above caller of
+// captureStack (skip 0 means the caller itself).
+func captureStack(skip, depth int) Stack {
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	if n == 0 {
+		return Stack{}
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	s := make(Stack, 0, n)
+	for {
+		f, more := frames.Next()
+		s = append(s, Frame{f.Function, f.File, f.Line})
+		if !more || len(s) == depth {
+			return s
+		}
+	}
+}
+
+// appendStackLines appends stack s stored under key to buf as lines following the current one: the key and then every
+// frame as function name on one line and tab-indented file and line on the next, both indented with a tab.
+func appendStackLines(buf []byte, key string, s Stack) []byte {
+	buf = append(buf, '\n')
+	buf = append(buf, key...)
+	buf = append(buf, ':')
+	for _, f := range s {
+		buf = append(buf, "\n\t"...)
+		buf = append(buf, f.Func...)
+		buf = append(buf, "\n\t\t"...)
+		buf = append(buf, f.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(f.Line), 10)
+	}
+	return buf
+}
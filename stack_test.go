@@ -0,0 +1,74 @@
+package twigsnake
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestErrorStackJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		depth  int
+		frames int // expected number of frames, -1 for no stack field
+	}{
+		{"default depth", DefaultStackDepth, 0},
+		{"limited", 2, 2},
+		{"single frame", 1, 1},
+		{"disabled", 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFormat(FormatJSON))
+			l.Named("x").SetStackDepth(tt.depth)
+			l.ErrorStack("m")
+
+			var got map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+			}
+			raw, ok := got["stack"]
+			if tt.frames < 0 {
+				if ok {
+					t.Errorf("stack = %s, want none", raw)
+				}
+				return
+			}
+			var frames []map[string]interface{}
+			if err := json.Unmarshal(raw, &frames); err != nil {
+				t.Fatalf("stack %s is not array of objects: %v", raw, err)
+			}
+			if tt.frames > 0 && len(frames) != tt.frames {
+				t.Errorf("got %d frames, want %d", len(frames), tt.frames)
+			}
+			if len(frames) == 0 {
+				t.Fatal("no frames captured")
+			}
+			for i, f := range frames {
+				_, isFunc := f["func"].(string)
+				_, isFile := f["file"].(string)
+				_, isLine := f["line"].(float64)
+				if len(f) != 3 || !isFunc || !isFile || !isLine {
+					t.Errorf("frame %d = %v, want {func, file, line}", i, f)
+				}
+			}
+			if fn := frames[0]["func"].(string); !strings.HasSuffix(fn, ".TestErrorStackJSON.func1") {
+				t.Errorf("innermost frame %q, want the test function", fn)
+			}
+			if file := frames[0]["file"].(string); !strings.HasSuffix(file, "/stack_test.go") {
+				t.Errorf("innermost file %q, want stack_test.go", file)
+			}
+		})
+	}
+}
+
+func TestErrorStackText(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	l.SetStackDepth(1)
+	l.ErrorStack("m")
+	got := lines(buf)
+	if len(got) != 4 || got[0] != "[ERROR] m" || got[1] != "stack:" ||
+		!strings.HasSuffix(got[2], ".TestErrorStackText") || !strings.Contains(got[3], "stack_test.go:") {
+		t.Errorf("output:\n%s\nwant message, stack key and a single frame", strings.Join(got, "\n"))
+	}
+}
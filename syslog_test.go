@@ -0,0 +1,131 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package twigsnake
+
+import (
+	"log/syslog"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	l, err := NewSyslog(LOG_INFO, "udp", conn.LocalAddr().String(), "app")
+	if err != nil {
+		t.Fatalf("NewSyslog: %v", err)
+	}
+	defer l.Close()
+
+	tests := []struct {
+		log      func(v ...interface{})
+		severity int
+	}{
+		{l.Emerg, LOG_EMERG},
+		{l.Alert, LOG_ALERT},
+		{l.Crit, LOG_CRIT},
+		{l.Error, LOG_ERROR},
+		{l.Warn, LOG_WARN},
+		{l.Notice, LOG_NOTICE},
+		{l.Info, LOG_INFO},
+	}
+	for _, tt := range tests {
+		msg := "message " + strconv.Itoa(tt.severity)
+		l.Debug("disabled")
+		tt.log(msg)
+
+		pkt := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(pkt)
+		if err != nil {
+			t.Fatalf("severity %d: %v", tt.severity, err)
+		}
+		got := string(pkt[:n])
+		// LOG_USER facility is 1.
+		if want := "<" + strconv.Itoa(1*8+tt.severity) + ">"; !strings.HasPrefix(got, want) {
+			t.Errorf("severity %d: packet %q, want priority %s", tt.severity, got, want)
+		}
+		if !strings.Contains(got, " app[") || !strings.HasSuffix(strings.TrimSuffix(got, "\n"), msg) {
+			t.Errorf("severity %d: packet %q lacks tag or message", tt.severity, got)
+		}
+	}
+}
+
+func TestFromSyslogWriter(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen on UDP: %v", err)
+	}
+	defer conn.Close()
+	w, err := syslog.Dial("udp", conn.LocalAddr().String(), syslog.LOG_LOCAL0|syslog.LOG_INFO, "ext")
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer w.Close()
+
+	if l, err := FromSyslogWriter(LOG_INFO, nil); err == nil || l != nil {
+		t.Errorf("FromSyslogWriter(nil) = %v, %v; want error", l, err)
+	}
+	if l, err := FromSyslogWriter(42, w); err == nil || l != nil {
+		t.Errorf("FromSyslogWriter(42) = %v, %v; want error", l, err)
+	}
+	l, err := FromSyslogWriter(LOG_NOTICE, w)
+	if err != nil {
+		t.Fatalf("FromSyslogWriter: %v", err)
+	}
+	l.Info("disabled")
+	l.Errorln("failed")
+	l.Notice("note")
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The writer isn't owned by the logger, so it stays open.
+	if err := w.Info("after close"); err != nil {
+		t.Errorf("writer closed by the logger: %v", err)
+	}
+
+	// LOG_LOCAL0 facility is 16.
+	want := []struct {
+		priority int
+		msg      string
+	}{{16*8 + LOG_ERROR, "failed"}, {16*8 + LOG_NOTICE, "note"}, {16*8 + LOG_INFO, "after close"}}
+	for _, m := range want {
+		pkt := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(pkt)
+		if err != nil {
+			t.Fatalf("%s: %v", m.msg, err)
+		}
+		got := string(pkt[:n])
+		if !strings.HasPrefix(got, "<"+strconv.Itoa(m.priority)+">") || !strings.Contains(got, " ext[") ||
+			!strings.HasSuffix(strings.TrimSuffix(got, "\n"), " "+m.msg) {
+			t.Errorf("packet %q, want priority %d, tag ext and message %q", got, m.priority, m.msg)
+		}
+	}
+}
+
+func TestNewSyslogErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		lvl              int
+		network, address string
+	}{
+		{"invalid level", 42, "udp", "127.0.0.1:514"},
+		{"unknown network", LOG_INFO, "bogus", "127.0.0.1:514"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if l, err := NewSyslog(tt.lvl, tt.network, tt.address, "app"); err == nil || l != nil {
+				t.Errorf("NewSyslog = %v, %v; want error", l, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package twigsnake
+
+import (
+	"errors"
+	"log/syslog"
+)
+
+// syslogPriorityWriter adapts one of syslog.Writer's per-priority methods to io.Writer. Every level gets writer of its
+// own, so they can be told apart by SetOutputLevels. Writers of connections owned by the logger close them on Close.
+type syslogPriorityWriter struct {
+	w     *syslog.Writer
+	write func(m string) error
+	owned bool
+}
+
+func (s *syslogPriorityWriter) Write(p []byte) (int, error) {
+	if err := s.write(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the syslog connection if it is owned by the logger (see NewSyslog).
+func (s *syslogPriorityWriter) Close() error {
+	if !s.owned {
+		return nil
+	}
+	return s.w.Close()
+}
+
+// FromSyslogWriter creates new Logger instance with specified logging level sending messages to an already connected
+// syslog writer. Every severity level is mapped to syslog priority of the same name, so e.g. Errorln ends up in w.Err.
+// Since syslog records timestamp and priority by itself, underlying loggers have empty prefixes and no flags set.
+func FromSyslogWriter(lvl int, w *syslog.Writer) (*Logger, error) {
+	if w == nil {
+		return nil, errors.New("nil syslog writer")
+	}
+
+	return newSyslogLogger(lvl, w, false)
+}
+
+// newSyslogLogger creates Logger writing to w, see FromSyslogWriter. With owned set, Close closes w.
+func newSyslogLogger(lvl int, w *syslog.Writer, owned bool) (*Logger, error) {
+	l, err := New(lvl, nil)
+	if err != nil {
+		return nil, err
+	}
+	methods := [8]func(m string) error{w.Emerg, w.Alert, w.Crit, w.Err, w.Warning, w.Notice, w.Info, w.Debug}
+	for i, lg := range l.loggers() {
+		lg.SetOutput(&syslogPriorityWriter{w: w, write: methods[i], owned: owned})
+		lg.SetPrefix("")
+		lg.SetFlags(0)
+	}
+	return l, nil
+}
+
+// NewSyslog creates new Logger instance with specified logging level sending messages to syslog daemon at address raddr
+// on network ("udp", "tcp" or "unix"; empty network and raddr connect to the local daemon), tagged with tag. Every
+// severity level is mapped to syslog priority of the same name with LOG_USER facility, as FromSyslogWriter does. Errors
+// of connecting to the daemon are returned. The connection is closed by the logger's Close.
+func NewSyslog(lvl int, network, raddr, tag string) (*Logger, error) {
+	if _, _, err := applyLevelPolicy(lvl); err != nil {
+		return nil, err
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	l, err := newSyslogLogger(lvl, w, true)
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+	return l, nil
+}
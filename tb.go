@@ -0,0 +1,49 @@
+package twigsnake
+
+import (
+	"strings"
+	"sync"
+)
+
+// TB is the subset of testing.TB used by NewTB, so that the package doesn't depend on testing.
+type TB interface {
+	Log(args ...interface{})
+	Cleanup(func())
+}
+
+// tbWriter writes every line as a separate tb.Log call. Calls are serialized and stop once the test completes, since
+// testing.T must not be logged to after that.
+type tbWriter struct {
+	mu   sync.Mutex
+	tb   TB
+	done bool
+}
+
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.done {
+		w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *tbWriter) stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
+
+// NewTB creates new Logger instance with specified logging level writing to the log of test or benchmark tb, so that
+// messages show up next to the test which produced them and only when it fails or runs with -v. Calls of tb.Log are
+// serialized with a mutex, so goroutines of the test can log concurrently and the race detector stays quiet. Messages
+// logged after the test completes are discarded instead of making the testing package panic.
+func NewTB(lvl int, tb TB) (*Logger, error) {
+	w := &tbWriter{tb: tb}
+	l, err := New(lvl, w)
+	if err != nil {
+		return nil, err
+	}
+	tb.Cleanup(w.stop)
+	return l, nil
+}
@@ -0,0 +1,91 @@
+package twigsnake
+
+import (
+	"log"
+	"sync"
+	"testing"
+)
+
+// fakeTB records Log calls. It has no synchronization of its own, so unserialized calls are caught by the race
+// detector.
+type fakeTB struct {
+	logs     []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Log(args ...interface{}) {
+	for _, a := range args {
+		tb.logs = append(tb.logs, a.(string))
+	}
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanups = append(tb.cleanups, fn)
+}
+
+func TestNewTB(t *testing.T) {
+	const goroutines, messages = 8, 50
+	tb := &fakeTB{}
+	l, err := NewTB(LOG_INFO, tb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetFlags(log.Lmsgprefix)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := l.Named("worker")
+			for i := 0; i < messages; i++ {
+				c.Info("m")
+				c.Debug("hidden")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(tb.logs) != goroutines*messages {
+		t.Fatalf("%d Log calls, want %d", len(tb.logs), goroutines*messages)
+	}
+	for _, s := range tb.logs {
+		if s != "[INFO] m logger=worker" {
+			t.Fatalf("logged %q", s)
+		}
+	}
+
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	l.Info("after test")
+	if n := len(tb.logs); n != goroutines*messages {
+		t.Errorf("%d Log calls after cleanup, want none", n-goroutines*messages)
+	}
+}
+
+func TestNewTBConcurrentWithTestingT(t *testing.T) {
+	l, err := NewTB(LOG_DEBUG, t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				l.Debugw("concurrent", "g", g, "i", i)
+			}
+		}(g)
+	}
+	// Concurrent tb.Log calls are checked by the race detector.
+	wg.Wait()
+}
+
+func TestNewTBInvalidLevel(t *testing.T) {
+	tb := &fakeTB{}
+	if l, err := NewTB(42, tb); err == nil || l != nil {
+		t.Errorf("NewTB(42) = %v, %v; want error", l, err)
+	}
+}
@@ -0,0 +1,23 @@
+package twigsnake
+
+// Transition prints state change on given level as "from -> to", with both values rendered like structured field
+// values, so that state machine transitions look the same everywhere. Nothing is rendered if the level is disabled.
+// Enable SetCallerFunc to see where the transition happened.
+func (l *Logger) Transition(level int, from, to interface{}) {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok || !l.EffectiveEnabled(level) {
+		return
+	}
+
+	r := l.base()
+	r.mu.Lock()
+	depth, enc := r.maxDepth, r.bytesEnc
+	r.mu.Unlock()
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = appendValue(*buf, from, depth, enc)
+	*buf = append(*buf, " -> "...)
+	*buf = appendValue(*buf, to, depth, enc)
+	l.output(level, string(*buf), nil)
+}
@@ -2,8 +2,17 @@ package twigsnake
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
 )
 
 // Log severity levels (as defined in RFC 5424 section 6.2.1):
@@ -21,7 +30,82 @@ const (
 // Logger is the logging object itself. Under the hood it has separate log.Logger instance for every severity level. All of them are
 // exported, so you can fine-tune them individually (set custom prefix, output and whatever log.Logger allows to to with it).
 type Logger struct {
-	logLevel int
+	pending    int64        // number of queued asynchronous writes, accessed atomically; kept first for alignment
+	dropped    int64        // number of asynchronous writes dropped because the queue was full, accessed atomically
+	levelGen   uint64       // incremented on every level change, accessed atomically
+	levelCache uint64       // level of named logger tagged with levelGen it was resolved at, see LogLevel
+	counts     [8]int64     // number of printed messages by level, accessed atomically
+	logLevel   int32        // accessed atomically
+	printLevel int32        // level of Print, Printf and Println, accessed atomically
+	levelMask  uint32       // enabled levels with maskActive bit set, zero if levels follow threshold; accessed atomically
+	skipEmpty  int32        // nonzero if empty messages are suppressed, see SetSkipEmpty; accessed atomically
+	trailMap   int32        // nonzero if trailing map arguments are fields, see SetTrailingMapFields; accessed atomically
+	fatalLevel int32        // level of Fatal, Fatalf and Fatalln, accessed atomically
+	clock      atomic.Value // clockFunc
+	sampler    atomic.Value // samplerFunc
+
+	debounced  sync.Map // debounce key -> *int64 holding time of the last message in nanoseconds, accessed atomically
+	components sync.Map // component name -> its logging level, see SetComponentLevel
+	meta       sync.Map // metadata key -> value, see SetMeta
+
+	root   *Logger         // logger which named logger belongs to, nil for root loggers
+	name   string          // component name of named logger
+	fields []Field         // context added with With
+	buffer *bufferedWriter // output buffer of loggers created with NewBuffered
+
+	mu            sync.Mutex // serializes writes and guards fields below
+	recent        ringBuffer
+	tails         map[chan string]struct{}
+	formatter     Formatter
+	headerDone    map[interface{}]bool // writers which already received formatter's header
+	buckets       [8]*tokenBucket
+	alertLevel    int
+	alertSink     io.Writer
+	lineEnding    string
+	maxDepth      int
+	callerSite    bool
+	callerFunc    bool
+	callerPkg     bool
+	stackDepth    int
+	bytesEnc      BytesEncoding
+	dedupWindow   time.Duration
+	dedup         [8]dedupStreak
+	facility      int
+	numericPrefix bool
+	outputFunc    func(level int) io.Writer
+	router        func(level int, msg string) io.Writer
+	outputLevels  map[interface{}]uint8 // writer -> bit mask of levels it accepts
+	detailOutput  io.Writer
+	auditOutput   io.Writer
+	auditMirror   bool
+	fieldOrder    map[string]int
+	sinks         []Sink
+	exports       []Sink // destinations added with NDJSONExport
+	timeKey       string
+	levelKey      string
+	messageKey    string
+	sectionFill   rune
+	sectionWidth  int
+	closeSummary  bool
+	summaryLevel  int
+	reqHeaders    []string
+	elapsed       bool
+	quoteMsg      bool
+	precision     [8]Precision
+	timeFormat    string
+	deterministic bool
+	start         time.Time
+	signalExit    int // exit status of FlushOnSignals, negative if it re-raises the signal
+	async         chan asyncWrite
+	asyncDrop     bool // drop writes instead of blocking when the queue is full, see SetAsyncOverflow
+	asyncDone     chan struct{}
+	batching      bool // writes are collected in batch, see beginBatch
+	batch         []batchWrite
+
+	lastResortMu         sync.Mutex // guards fields below
+	lastResort           io.Writer
+	lastResortAt         time.Time
+	lastResortSuppressed int
 
 	// Collection of standard loggers for every severity level:
 	EmergLogger   *log.Logger
@@ -34,9 +118,12 @@ type Logger struct {
 	DebugLogger   *log.Logger
 }
 
+// errInvalidLevel is returned for severity levels outside of LOG_EMERG..LOG_DEBUG range.
+var errInvalidLevel = errors.New("invalid severity level")
+
 func checkLogLevel(lvl int) error {
 	if !(lvl >= 0 && lvl <= 7) {
-		return errors.New("invalid severity level")
+		return errInvalidLevel
 	}
 	return nil
 }
@@ -51,219 +138,698 @@ func checkLogLevel(lvl int) error {
 //	Notification level	- [NOTICE]
 //	Informational level	- [INFO]
 //	Debug level		- [DEBUG]
-func New(lvl int, dest io.Writer) (*Logger, error) {
-	if err := checkLogLevel(lvl); err != nil {
-		return nil, err
+//
+// Options (see WithFlags, WithPrefixes and WithTimeFormat) are applied in order after the defaults are set up, so they
+// can override any of them. Without options the defaults above apply.
+func New(lvl int, dest io.Writer, opts ...Option) (*Logger, error) {
+	lvl, ok, _ := applyLevelPolicy(lvl)
+	if !ok {
+		return nil, errInvalidLevel
 
 	}
 
-	return &Logger{
-		lvl,
-		log.New(dest, "[EMERG] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[ALERT] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[CRIT] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[ERROR] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[WARN] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[NOTICE] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[INFO] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-		log.New(dest, "[DEBUG] ", log.Ldate|log.Ltime|log.Lmsgprefix),
-	}, nil
+	l := &Logger{
+		logLevel:      int32(lvl),
+		printLevel:    LOG_INFO,
+		maxDepth:      DefaultMaxDepth,
+		stackDepth:    DefaultStackDepth,
+		fatalLevel:    LOG_CRIT,
+		signalExit:    -1,
+		lineEnding:    "\n",
+		lastResort:    os.Stderr,
+		start:         time.Now(),
+		formatter:     textFormatter{},
+		EmergLogger:   log.New(dest, "[EMERG] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		AlertLogger:   log.New(dest, "[ALERT] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		CritLogger:    log.New(dest, "[CRIT] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		ErrorLogger:   log.New(dest, "[ERROR] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		WarningLogger: log.New(dest, "[WARN] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		NoticeLogger:  log.New(dest, "[NOTICE] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		InfoLogger:    log.New(dest, "[INFO] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+		DebugLogger:   log.New(dest, "[DEBUG] ", log.Ldate|log.Ltime|log.Lmsgprefix),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l, nil
 
 }
 
-// LogLevel returns current logging level.
+// NewNop creates new Logger instance which discards all messages. It is a safe placeholder where logger is required but
+// no output is wanted.
+func NewNop() *Logger {
+	l, _ := New(LOG_EMERG, ioutil.Discard)
+	return l
+}
+
+// LogLevel returns current logging level. For named loggers it is the level of their component (see
+// SetComponentLevel).
 func (l *Logger) LogLevel() int {
-	return l.logLevel
+	if l.root != nil {
+		return l.cachedLevel()
+	}
+	return int(atomic.LoadInt32(&l.logLevel))
+}
+
+// EffectiveEnabled reports whether messages of given level are actually printed right now, taking into account
+// logging level and, for named loggers, level of their component, or the mask of enabled levels when one is set with
+// SetEnabledLevels and friends. All printing methods consult it, so it is the single source of truth on what gets
+// logged. It is false for invalid levels.
+func (l *Logger) EffectiveEnabled(level int) bool {
+	if level < LOG_EMERG || level > LOG_DEBUG {
+		return false
+	}
+	if mask := atomic.LoadUint32(&l.base().levelMask); mask != 0 {
+		return mask&(1<<uint(level)) != 0
+	}
+	return level <= l.LogLevel()
 }
 
-// SetLogLevel sets logging level. Returns error if specified level is incorrect.
+// SetLogLevel sets logging level. Returns error if specified level is incorrect, unless another policy is set with
+// SetInvalidLevelPolicy. For named loggers it sets the level of their component, the same as SetComponentLevel does.
+// Otherwise it sets the level of the logger Named or With was originally called on and also drops the mask of enabled
+// levels set with SetEnabledLevels and friends. Level is stored atomically, so it is safe to change it while other
+// goroutines are logging: every message is checked against either the old or the new level, without locking on the hot
+// path.
 func (l *Logger) SetLogLevel(lvl int) error {
-	if err := checkLogLevel(lvl); err != nil {
+	lvl, ok, err := applyLevelPolicy(lvl)
+	if !ok {
 		return err
 	}
-	l.logLevel = lvl
+	if l.name != "" {
+		l.root.SetComponentLevel(l.name, lvl)
+		return nil
+	}
+	r := l.base()
+	atomic.StoreInt32(&r.logLevel, int32(lvl))
+	atomic.StoreUint32(&r.levelMask, 0)
+	atomic.AddUint64(&r.levelGen, 1)
 	return nil
 }
 
+// IncreaseVerbosity moves logging level one step towards LOG_DEBUG, unless it is already there, and returns the new
+// level. It is safe to call concurrently, e.g. from a signal handler.
+func (l *Logger) IncreaseVerbosity() int {
+	return l.addLevel(1)
+}
+
+// DecreaseVerbosity moves logging level one step towards LOG_EMERG, unless it is already there, and returns the new
+// level. It is safe to call concurrently, e.g. from a signal handler.
+func (l *Logger) DecreaseVerbosity() int {
+	return l.addLevel(-1)
+}
+
+// addLevel atomically adds delta to logging level, clamping the result to valid range, and returns the new level.
+func (l *Logger) addLevel(delta int) int {
+	clamp := func(lvl int) int {
+		if lvl < LOG_EMERG {
+			return LOG_EMERG
+		}
+		if lvl > LOG_DEBUG {
+			return LOG_DEBUG
+		}
+		return lvl
+	}
+
+	if l.name != "" {
+		lvl := clamp(l.LogLevel() + delta)
+		l.root.SetComponentLevel(l.name, lvl)
+		return lvl
+	}
+	r := l.base()
+	for {
+		old := atomic.LoadInt32(&r.logLevel)
+		lvl := clamp(int(old) + delta)
+		if atomic.CompareAndSwapInt32(&r.logLevel, old, int32(lvl)) {
+			atomic.AddUint64(&r.levelGen, 1)
+			return lvl
+		}
+	}
+}
+
+// SetAlertSink directs a copy of every message with severity minLevel or higher (i.e. numerically less or equal) to w, in
+// addition to the regular output of its level. This allows to forward urgent messages to pager or alerting daemon while
+// keeping the main log intact. Copies are formatted with the same prefix and flags as the original message. Passing nil
+// writer disables the sink.
+func (l *Logger) SetAlertSink(minLevel int, w io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.alertLevel = minLevel
+	r.alertSink = w
+}
+
+// SetLineEnding sets the sequence terminating every emitted line, e.g. "\r\n" for consumers expecting Windows line
+// endings. Line break at the end of the message itself (such as the one added by Println-style methods) is replaced
+// with s rather than duplicated. Empty string restores the default "\n".
+func (l *Logger) SetLineEnding(s string) {
+	if s == "" {
+		s = "\n"
+	}
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lineEnding = s
+}
+
+// SetSkipEmpty enables or disables suppression of messages which are empty or consist of whitespace only, such as
+// the ones printed by Infoln() without arguments or by Printf-style methods with format rendering to nothing. Messages
+// carrying structured fields are printed regardless. It is disabled by default.
+func (l *Logger) SetSkipEmpty(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&l.base().skipEmpty, v)
+}
+
+// skipsEmpty reports whether empty messages are suppressed, see SetSkipEmpty.
+func (l *Logger) skipsEmpty() bool {
+	return atomic.LoadInt32(&l.base().skipEmpty) != 0
+}
+
+// SetOutputFunc sets function choosing destination of every message by its level, e.g. to separate logs of different
+// tenants without reconstructing loggers. The function is called for each emitted message while logger's internal
+// lock is held, so it must be fast and must not log through the same logger. Returning nil writer drops the message
+// from the main output (alert sink and ring buffer still receive it). Nil function restores routing to outputs of
+// underlying standard loggers.
+func (l *Logger) SetOutputFunc(fn func(level int) io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputFunc = fn
+}
+
+// SetContentRouter sets function choosing destination of every message by its level and text, e.g. to send lines
+// tagged "[AUDIT]" to a separate file. Returning nil writer sends the message to its regular output (see
+// SetOutputFunc), so the common case should return nil as fast as possible. The router is consulted last, right before
+// writing, with the final message text; it doesn't affect sinks, alert sink and ring buffer. Like output function, it
+// is called while logger's internal lock is held, so it must not log through the same logger. Nil function removes the
+// router.
+func (l *Logger) SetContentRouter(fn func(level int, msg string) io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.router = fn
+}
+
+// Outputs returns current destinations of every severity level, indexed by level. Writers are taken from the underlying
+// standard loggers, so changes made directly through exported loggers are reflected as well.
+func (l *Logger) Outputs() [8]io.Writer {
+	var outputs [8]io.Writer
+	for lvl, lg := range l.loggers() {
+		outputs[lvl] = lg.Writer()
+	}
+	return outputs
+}
+
+// SetOutput sets output of underlying loggers of all levels to w, e.g. to switch from standard output to a file at
+// runtime. Outputs of individual levels can still be overridden afterwards through the exported loggers. Messages being
+// written concurrently go entirely to either the old or the new output.
+func (l *Logger) SetOutput(w io.Writer) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, lg := range r.loggers() {
+		lg.SetOutput(w)
+	}
+}
+
+// SetFlags sets output flags of underlying loggers of all levels, e.g. to switch every level to UTC timestamps or add
+// log.Lshortfile globally. Prefixes are not affected. Flags of individual levels can still be overridden afterwards
+// through the exported loggers.
+func (l *Logger) SetFlags(flag int) {
+	r := l.base()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, lg := range r.loggers() {
+		lg.SetFlags(flag)
+	}
+}
+
+// loggers returns underlying standard loggers indexed by severity level.
+func (l *Logger) loggers() [8]*log.Logger {
+	return [8]*log.Logger{
+		l.EmergLogger,
+		l.AlertLogger,
+		l.CritLogger,
+		l.ErrorLogger,
+		l.WarningLogger,
+		l.NoticeLogger,
+		l.InfoLogger,
+		l.DebugLogger,
+	}
+}
+
+// output builds entry from message s and structured fields, formats it with prefix and flags of the standard logger of
+// level lvl and writes it to that logger's output, duplicating the line to the alert sink and ring buffer when
+// required. Level check is the caller's responsibility. Call site, if needed, is the caller of the function which
+// called output.
+func (l *Logger) output(lvl int, s string, fields []Field) {
+	l.outputAt(lvl, s, fields, 0, time.Time{})
+}
+
+// outputAt is like output, but takes call site as program counter pc (see addCaller) and message time t. Zero pc means
+// the caller of the function which called output, zero t means current time of the logger's clock.
+func (l *Logger) outputAt(lvl int, s string, fields []Field, pc uintptr, t time.Time) {
+	r := l.base()
+	if len(fields) == 0 && r.skipsEmpty() && strings.TrimSpace(s) == "" {
+		return
+	}
+
+	lg := r.loggers()[lvl]
+	fields = resolveLazy(l.withName(fields))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entry(lg, lvl, s, fields)
+	now := e.Time
+	if !t.IsZero() {
+		e.Time = t
+	}
+	r.addCaller(&e, pc, 3)
+	if r.admit(&e, now) {
+		r.emit(&e)
+	}
+}
+
+// admit reports whether entry e passes deduplication (see SetDedup) and the token bucket of its level (see
+// SetTokenBucket), which is charged at time now rather than at the time of e, as the latter may be explicitly set. If e
+// passes the bucket after some messages were suppressed, a line reporting them is emitted first. Must be called with
+// l.mu held.
+func (l *Logger) admit(e *Entry, now time.Time) bool {
+	if l.dedupe(e) {
+		return false
+	}
+	if b := l.buckets[e.Level]; b != nil {
+		if !b.allow(now) {
+			return false
+		}
+		if n := b.takeSuppressed(); n > 0 {
+			summary := *e
+			summary.Message = fmt.Sprintf("(suppressed %d messages)", n)
+			summary.Fields = nil
+			l.emit(&summary)
+		}
+	}
+	return true
+}
+
+// entry builds entry of level lvl for message s, taking prefix and flags from lg. Caller file and line are left for the
+// caller to fill in. Must be called with l.mu held.
+func (l *Logger) entry(lg *log.Logger, lvl int, s string, fields []Field) Entry {
+	flag := lg.Flags()
+	if l.callerSite && !callerNeeded(flag) {
+		flag |= log.Lshortfile
+	}
+	return Entry{
+		Time:          l.now(),
+		Level:         lvl,
+		Message:       trimEOL(s),
+		Fields:        fields,
+		Prefix:        sanitizePrefix(lg.Prefix()),
+		Flags:         flag,
+		MaxDepth:      l.maxDepth,
+		BytesEncoding: l.bytesEnc,
+	}
+}
+
+// emit formats entry e and writes it to the output of its level, duplicating the line to the alert sink and ring buffer
+// when required. Must be called with l.mu held.
+func (l *Logger) emit(e *Entry) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	atomic.AddInt64(&l.counts[e.Level], 1)
+	e.TimeKey, e.LevelKey, e.MessageKey = l.timeKey, l.levelKey, l.messageKey
+	if l.elapsed {
+		e.Start = l.start
+	}
+	if l.deterministic {
+		e.Time = deterministicTime
+		if l.elapsed {
+			e.Start = deterministicTime
+		}
+	}
+	e.QuoteMessage = l.quoteMsg
+	e.TimePrecision = l.precision[e.Level]
+	e.TimeFormat = l.timeFormat
+	e.Facility = l.facility
+	f := l.formatter
+	*buf = l.formatLine(*buf, f, e)
+	if len(l.sinks) > 0 {
+		l.emitSinks(e, l.sinks)
+	} else if w := l.writerFor(e); w != nil {
+		l.write(f, w, e.Level, *buf)
+	}
+	if len(l.exports) > 0 {
+		l.emitSinks(e, l.exports)
+	}
+	if l.alertSink != nil && e.Level <= l.alertLevel {
+		l.write(f, l.alertSink, e.Level, *buf)
+	}
+	l.record(*buf)
+}
+
+// formatLine appends complete line representing entry e formatted with f to buf. Must be called with l.mu held.
+func (l *Logger) formatLine(buf []byte, f Formatter, e *Entry) []byte {
+	if l.fieldOrder != nil && len(e.Fields) > 1 {
+		ordered := *e
+		ordered.Fields = orderFields(e.Fields, l.fieldOrder)
+		e = &ordered
+	}
+	if _, ok := f.(*rfc5424Formatter); l.numericPrefix && !ok {
+		buf = appendEntryPriority(buf, e, l.facility)
+	}
+	buf = f.Format(buf, e)
+	return append(buf, l.lineEnding...)
+}
+
+// writerFor returns destination of entry e: the writer chosen by content router, if it chooses any, otherwise the writer
+// chosen by output function or the output of the standard logger of e's level. Must be called with l.mu held.
+func (l *Logger) writerFor(e *Entry) io.Writer {
+	if l.router != nil {
+		if w := l.router(e.Level, e.Message); w != nil {
+			return w
+		}
+	}
+	if l.outputFunc != nil {
+		return l.outputFunc(e.Level)
+	}
+	return l.loggers()[e.Level].Writer()
+}
+
+// write writes line of level lvl formatted with f to w, preceding it with formatter's header if w hasn't received it
+// yet. Lines of levels w doesn't accept according to SetOutputLevels are dropped. Must be called with l.mu held.
+func (l *Logger) write(f Formatter, w io.Writer, lvl int, line []byte) {
+	if !l.outputAccepts(w, lvl) {
+		return
+	}
+	if hf, ok := f.(headerFormatter); ok && !l.headerDone[writerKey(w)] {
+		if l.headerDone == nil {
+			l.headerDone = make(map[interface{}]bool)
+		}
+		l.headerDone[writerKey(w)] = true
+		l.writeRaw(w, append(hf.Header(nil), l.lineEnding...))
+	}
+	l.writeRaw(w, line)
+}
+
+// writeRaw writes p to w directly or, in asynchronous mode, queues a copy of it for the background writer. Must be
+// called with l.mu held.
+func (l *Logger) writeRaw(w io.Writer, p []byte) {
+	if l.batching {
+		key := writerKey(w)
+		for i := range l.batch {
+			if writerKey(l.batch[i].w) == key {
+				l.batch[i].p = append(l.batch[i].p, p...)
+				return
+			}
+		}
+		l.batch = append(l.batch, batchWrite{w, append([]byte(nil), p...)})
+		return
+	}
+	if l.async != nil {
+		item := asyncWrite{w, append([]byte(nil), p...)}
+		atomic.AddInt64(&l.pending, 1)
+		if !l.asyncDrop {
+			l.async <- item
+			return
+		}
+		select {
+		case l.async <- item:
+		default:
+			atomic.AddInt64(&l.pending, -1)
+			atomic.AddInt64(&l.dropped, 1)
+		}
+		return
+	}
+	if _, err := w.Write(p); err != nil {
+		l.writeFailed(err)
+	}
+}
+
+// batchWrite is data collected for a single writer while a batch is open, see beginBatch.
+type batchWrite struct {
+	w io.Writer
+	p []byte
+}
+
+// beginBatch opens a batch: until endBatch is called, writes are collected per writer instead of being performed, so
+// that multi-line output such as Banner reaches every destination in a single write and nothing else, not even other
+// processes appending to the same file, can get in between. Must be called with l.mu held.
+func (l *Logger) beginBatch() {
+	l.batching = true
+}
+
+// endBatch closes the batch opened with beginBatch and writes data collected for every writer at once, in order of
+// their first use. Must be called with l.mu held.
+func (l *Logger) endBatch() {
+	batch := l.batch
+	l.batch, l.batching = nil, false
+	for _, b := range batch {
+		l.writeRaw(b.w, b.p)
+	}
+}
+
+// writerID identifies writer of non-comparable type by its dynamic type and the address of the value it holds.
+type writerID struct {
+	t reflect.Type
+	p unsafe.Pointer
+}
+
+// writerKey returns value identifying writer w suitable for use as a map key. Writers of comparable types, such as
+// pointers, are keys themselves. Writers of other types, such as func adapters or structs holding slices, are identified
+// by the value the interface holds: copies of one interface value share the key, while distinct writers of the same
+// type get keys of their own.
+func writerKey(w io.Writer) interface{} {
+	if t := reflect.TypeOf(w); t != nil && !t.Comparable() {
+		return writerID{t, (*[2]unsafe.Pointer)(unsafe.Pointer(&w))[1]}
+	}
+	return w
+}
+
 // Emerg prints emergency messages. They will appear on any logging level. Handles arguments in the same manner as log.Print.
 func (l *Logger) Emerg(v ...interface{}) {
-	l.EmergLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_EMERG) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_EMERG, fmt.Sprint(v...), fields)
+	}
 }
 
 // Emergf prints emergency messages. They will appear on any logging level. Handles arguments in the same manner as log.Printf.
 func (l *Logger) Emergf(format string, v ...interface{}) {
-	l.EmergLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_EMERG) {
+		l.output(LOG_EMERG, fmt.Sprintf(format, v...), nil)
+	}
 }
 
 // Emergln prints emergency messages. They will appear on any logging level. Handles arguments in the same manner as log.Println.
 func (l *Logger) Emergln(v ...interface{}) {
-	l.EmergLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_EMERG) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_EMERG, fmt.Sprintln(v...), fields)
+	}
 }
 
 // Alert prints alert messages. They will appear on logging level twigsnake.LOG_ALERT and higher. Handles arguments in the same
 // manner as log.Print.
 func (l *Logger) Alert(v ...interface{}) {
-	if l.logLevel >= LOG_ALERT {
-		l.AlertLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_ALERT) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_ALERT, fmt.Sprint(v...), fields)
 	}
 }
 
 // Alertf prints alert messages. They will appear on logging level twigsnake.LOG_ALERT and higher. Handles arguments in the same
 // manner as log.Printf.
 func (l *Logger) Alertf(format string, v ...interface{}) {
-	if l.logLevel >= LOG_ALERT {
-		l.AlertLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_ALERT) {
+		l.output(LOG_ALERT, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Alertln prints alert messages. They will appear on logging level twigsnake.LOG_ALERT and higher. Handles arguments in the same
 // manner as log.Println.
 func (l *Logger) Alertln(v ...interface{}) {
-	if l.logLevel >= LOG_ALERT {
-		l.AlertLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_ALERT) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_ALERT, fmt.Sprintln(v...), fields)
 	}
 }
 
 // Crit prints critical messages. They will appear on logging level twigsnake.LOG_CRIT and higher. Handles arguments in the same
 // manner as log.Print.
 func (l *Logger) Crit(v ...interface{}) {
-	if l.logLevel >= LOG_CRIT {
-		l.CritLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_CRIT) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_CRIT, fmt.Sprint(v...), fields)
 	}
 }
 
 // Critf prints critical messages. They will appear on logging level twigsnake.LOG_CRIT and higher. Handles arguments in the same
 // manner as log.Printf.
 func (l *Logger) Critf(format string, v ...interface{}) {
-	if l.logLevel >= LOG_CRIT {
-		l.CritLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_CRIT) {
+		l.output(LOG_CRIT, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Critln prints critical messages. They will appear on logging level twigsnake.LOG_CRIT and higher. Handles arguments in the same
 // manner as log.Println.
 func (l *Logger) Critln(v ...interface{}) {
-	if l.logLevel >= LOG_CRIT {
-		l.CritLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_CRIT) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_CRIT, fmt.Sprintln(v...), fields)
 	}
 }
 
 // Error prints error messages. They will appear on logging level twigsnake.LOG_ERROR and higher. Handles arguments in the same
 // manner as log.Print.
 func (l *Logger) Error(v ...interface{}) {
-	if l.logLevel >= LOG_ERROR {
-		l.ErrorLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_ERROR) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_ERROR, fmt.Sprint(v...), fields)
 	}
 }
 
 // Errorf prints error messages. They will appear on logging level twigsnake.LOG_ERROR and higher. Handles arguments in the same
 // manner as log.Printf.
 func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.logLevel >= LOG_ERROR {
-		l.ErrorLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_ERROR) {
+		l.output(LOG_ERROR, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Errorln prints error messages. They will appear on logging level twigsnake.LOG_ERROR and higher. Handles arguments in the same
 // manner as log.Println.
 func (l *Logger) Errorln(v ...interface{}) {
-	if l.logLevel >= LOG_ERROR {
-		l.ErrorLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_ERROR) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_ERROR, fmt.Sprintln(v...), fields)
 	}
 }
 
+// ErrorReturn prints err as error message and returns it unchanged, so that error can be logged and propagated in one
+// statement: return logger.ErrorReturn(doThing()). Nil error is not logged. Message will appear on logging level
+// twigsnake.LOG_ERROR and higher.
+func (l *Logger) ErrorReturn(err error) error {
+	if err != nil && l.EffectiveEnabled(LOG_ERROR) {
+		l.output(LOG_ERROR, err.Error(), nil)
+	}
+	return err
+}
+
 // Warn prints warning messages. They will appear on logging level twigsnake.LOG_WARN and higher. Handles arguments in the same
 // manner as log.Print.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.logLevel >= LOG_WARN {
-		l.WarningLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_WARN) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_WARN, fmt.Sprint(v...), fields)
 	}
 }
 
 // Warnf prints warning messages. They will appear on logging level twigsnake.LOG_WARN and higher. Handles arguments in the same
 // manner as log.Printf.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.logLevel >= LOG_WARN {
-		l.WarningLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_WARN) {
+		l.output(LOG_WARN, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Warnln prints warning messages. They will appear on logging level twigsnake.LOG_WARN and higher. Handles arguments in the same
 // manner as log.Println.
 func (l *Logger) Warnln(v ...interface{}) {
-	if l.logLevel >= LOG_WARN {
-		l.WarningLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_WARN) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_WARN, fmt.Sprintln(v...), fields)
+	}
+}
+
+// WarnReturn prints err as warning message and returns it unchanged, so that error can be logged and propagated in one
+// statement: return logger.WarnReturn(doThing()). Nil error is not logged. Message will appear on logging level
+// twigsnake.LOG_WARN and higher.
+func (l *Logger) WarnReturn(err error) error {
+	if err != nil && l.EffectiveEnabled(LOG_WARN) {
+		l.output(LOG_WARN, err.Error(), nil)
 	}
+	return err
 }
 
 // Notice prints notification messages. They will appear on logging level twigsnake.LOG_NOTICE and higher. Handles arguments in the
 // same manner as log.Print.
 func (l *Logger) Notice(v ...interface{}) {
-	if l.logLevel >= LOG_NOTICE {
-		l.NoticeLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_NOTICE) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_NOTICE, fmt.Sprint(v...), fields)
 	}
 }
 
 // Noticef prints notification messages. They will appear on logging level twigsnake.LOG_NOTICE and higher. Handles arguments in the
 // same manner as log.Printf.
 func (l *Logger) Noticef(format string, v ...interface{}) {
-	if l.logLevel >= LOG_NOTICE {
-		l.NoticeLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_NOTICE) {
+		l.output(LOG_NOTICE, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Noticeln prints notification messages. They will appear on logging level twigsnake.LOG_NOTICE and higher. Handles arguments in the
 // same manner as log.Println.
 func (l *Logger) Noticeln(v ...interface{}) {
-	if l.logLevel >= LOG_NOTICE {
-		l.NoticeLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_NOTICE) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_NOTICE, fmt.Sprintln(v...), fields)
 	}
 }
 
 // Info prints informational messages. They will appear on logging level twigsnake.LOG_INFO and higher. Handles arguments in the same
 // manner as log.Print.
 func (l *Logger) Info(v ...interface{}) {
-	if l.logLevel >= LOG_INFO {
-		l.InfoLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_INFO) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_INFO, fmt.Sprint(v...), fields)
 	}
 }
 
 // Infof prints informational messages. They will appear on logging level twigsnake.LOG_INFO and higher. Handles arguments in the same
 // manner as log.Printf.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.logLevel >= LOG_INFO {
-		l.InfoLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_INFO) {
+		l.output(LOG_INFO, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Infoln prints informational messages. They will appear on logging level twigsnake.LOG_INFO and higher. Handles arguments in the same
 // manner as log.Println.
 func (l *Logger) Infoln(v ...interface{}) {
-	if l.logLevel >= LOG_INFO {
-		l.InfoLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_INFO) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_INFO, fmt.Sprintln(v...), fields)
 	}
 }
 
 // Debug prints debugging messages. They will appear only on logging level twigsnake.LOG_DEBUG. Handles arguments in the same manner
 // as log.Print.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.logLevel >= LOG_DEBUG {
-		l.DebugLogger.Print(v...)
+	if l.EffectiveEnabled(LOG_DEBUG) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_DEBUG, fmt.Sprint(v...), fields)
 	}
 }
 
 // Debugf prints debugging messages. They will appear only on logging level twigsnake.LOG_DEBUG. Handles arguments in the same manner
 // as log.Printf.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.logLevel >= LOG_DEBUG {
-		l.DebugLogger.Printf(format, v...)
+	if l.EffectiveEnabled(LOG_DEBUG) {
+		l.output(LOG_DEBUG, fmt.Sprintf(format, v...), nil)
 	}
 }
 
 // Debugln prints debugging messages. They will appear only on logging level twigsnake.LOG_DEBUG. Handles arguments in the same manner
 // as log.Println.
 func (l *Logger) Debugln(v ...interface{}) {
-	if l.logLevel >= LOG_DEBUG {
-		l.DebugLogger.Println(v...)
+	if l.EffectiveEnabled(LOG_DEBUG) {
+		v, fields := l.trailingFields(v)
+		l.output(LOG_DEBUG, fmt.Sprintln(v...), fields)
 	}
 }
@@ -0,0 +1,536 @@
+package twigsnake
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testTime is the frozen time of loggers created by newTestLogger.
+var testTime = time.Date(2021, 3, 5, 14, 30, 15, 123456000, time.UTC)
+
+// newTestLogger returns logger of level lvl writing to the returned buffer, with clock frozen at testTime and no
+// timestamps, so that lines can be compared verbatim. Options are applied after these defaults.
+func newTestLogger(t testing.TB, lvl int, opts ...Option) (*Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	defaults := []Option{WithClock(func() time.Time { return testTime }), WithFlags(log.Lmsgprefix)}
+	l, err := New(lvl, &buf, append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("New(%d) failed: %v", lvl, err)
+	}
+	return l, &buf
+}
+
+// lines returns lines written to buf, without line breaks.
+func lines(buf *bytes.Buffer) []string {
+	s := strings.TrimSuffix(buf.String(), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// funcWriter adapts function to io.Writer. Functions are not comparable, so it stands for writers which can't be used
+// as map keys directly.
+type funcWriter func(p []byte) (int, error)
+
+func (f funcWriter) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// bufferWriter returns funcWriter appending to buf.
+func bufferWriter(buf *bytes.Buffer) funcWriter {
+	return func(p []byte) (int, error) {
+		return buf.Write(p)
+	}
+}
+
+func TestNewInvalidLevel(t *testing.T) {
+	for _, lvl := range []int{-1, 8, 100} {
+		if l, err := New(lvl, nil); err == nil || l != nil {
+			t.Errorf("New(%d) = %v, %v; want error", lvl, l, err)
+		}
+	}
+}
+
+func TestLevelMethods(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_NOTICE)
+	l.Emerg("emerg")
+	l.Alertf("alert %d", 1)
+	l.Critln("crit")
+	l.Error("error")
+	l.Warnf("warn %s", "x")
+	l.Noticeln("notice")
+	l.Info("info")
+	l.Debugf("debug")
+
+	want := []string{"[EMERG] emerg", "[ALERT] alert 1", "[CRIT] crit", "[ERROR] error", "[WARN] warn x",
+		"[NOTICE] notice"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestErrorReturn(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		name  string
+		level int
+		call  func(l *Logger, err error) error
+		err   error
+		want  string
+	}{
+		{"ErrorReturn", LOG_ERROR, (*Logger).ErrorReturn, boom, "[ERROR] boom\n"},
+		{"ErrorReturn nil", LOG_DEBUG, (*Logger).ErrorReturn, nil, ""},
+		{"ErrorReturn disabled", LOG_CRIT, (*Logger).ErrorReturn, boom, ""},
+		{"WarnReturn", LOG_WARN, (*Logger).WarnReturn, boom, "[WARN] boom\n"},
+		{"WarnReturn nil", LOG_DEBUG, (*Logger).WarnReturn, nil, ""},
+		{"WarnReturn disabled", LOG_ERROR, (*Logger).WarnReturn, boom, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, tt.level)
+			if got := tt.call(l, tt.err); got != tt.err {
+				t.Errorf("returned %v, want %v", got, tt.err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterKey(t *testing.T) {
+	var a, b bytes.Buffer
+	wa, wb := bufferWriter(&a), bufferWriter(&b)
+	var iface interface{ Write([]byte) (int, error) } = wa
+
+	tests := []struct {
+		name string
+		x, y funcWriter
+		same bool
+	}{
+		{"same writer", wa, wa, true},
+		{"distinct writers of one type", wa, wb, false},
+		{"copy of interface value", iface.(funcWriter), wa, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writerKey(tt.x) == writerKey(tt.y); got != tt.same {
+				t.Errorf("keys equal = %v, want %v", got, tt.same)
+			}
+		})
+	}
+	if writerKey(&a) != writerKey(&a) || writerKey(&a) == writerKey(&b) {
+		t.Error("pointer writers must be keyed by identity")
+	}
+}
+
+func TestAlertSink(t *testing.T) {
+	var sink bytes.Buffer
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	l.With("k", "v").SetAlertSink(LOG_ALERT, &sink)
+
+	tests := []struct {
+		log  func(v ...interface{})
+		msg  string
+		sink bool
+	}{
+		{l.Emerg, "emerg", true},
+		{l.Alert, "alert", true},
+		{l.Crit, "crit", false},
+		{l.Info, "info", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			buf.Reset()
+			sink.Reset()
+			tt.log(tt.msg)
+			if !strings.Contains(buf.String(), tt.msg) {
+				t.Errorf("main output %q lacks message", buf.String())
+			}
+			if got := sink.String() == buf.String(); got != tt.sink {
+				t.Errorf("sink got copy = %v, want %v (sink %q)", got, tt.sink, sink.String())
+			}
+		})
+	}
+}
+
+func TestSetLineEnding(t *testing.T) {
+	tests := []struct {
+		name   string
+		ending string
+		log    func(l *Logger)
+		want   string
+	}{
+		{"default", "", func(l *Logger) { l.Info("a") }, "[INFO] a\n"},
+		{"crlf", "\r\n", func(l *Logger) { l.Info("a") }, "[INFO] a\r\n"},
+		{"crlf replaces line break of Println", "\r\n", func(l *Logger) { l.Infoln("a") }, "[INFO] a\r\n"},
+		{"crlf replaces crlf of message", "\r\n", func(l *Logger) { l.Info("a\r\n") }, "[INFO] a\r\n"},
+		{"set on With logger", "\r\n", func(l *Logger) { l.With().Info("a") }, "[INFO] a\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			l.With().SetLineEnding(tt.ending)
+			tt.log(l)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSkipEmpty(t *testing.T) {
+	tests := []struct {
+		name    string
+		log     func(l *Logger)
+		printed bool
+	}{
+		{"Println without arguments", func(l *Logger) { l.Infoln() }, false},
+		{"Printf rendering to nothing", func(l *Logger) { l.Infof("%s", "") }, false},
+		{"whitespace only", func(l *Logger) { l.Info(" \t ") }, false},
+		{"LogAt", func(l *Logger) { l.LogAt(testTime, LOG_INFO) }, false},
+		{"message", func(l *Logger) { l.Info("x") }, true},
+		{"fields without message", func(l *Logger) { l.Infow("", "k", "v") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG)
+			tt.log(l)
+			if buf.Len() == 0 {
+				t.Fatal("nothing printed with SetSkipEmpty disabled")
+			}
+
+			buf.Reset()
+			l.With().SetSkipEmpty(true)
+			tt.log(l)
+			if got := buf.Len() > 0; got != tt.printed {
+				t.Errorf("printed = %v, want %v (output %q)", got, tt.printed, buf.String())
+			}
+		})
+	}
+}
+
+func TestSetOutput(t *testing.T) {
+	l, old := newTestLogger(t, LOG_DEBUG)
+	var buf bytes.Buffer
+	l.Named("x").SetOutput(&buf)
+	for lvl, lg := range l.loggers() {
+		if lg.Writer() != &buf {
+			t.Errorf("level %d writes to %v", lvl, lg.Writer())
+		}
+	}
+
+	logs := []func(v ...interface{}){l.Emerg, l.Alert, l.Crit, l.Error, l.Warn, l.Notice, l.Info, l.Debug}
+	for _, fn := range logs {
+		fn("m")
+	}
+	if got := len(lines(&buf)); got != len(logs) {
+		t.Errorf("%d lines written to the new output, want %d", got, len(logs))
+	}
+	if old.Len() != 0 {
+		t.Errorf("old output received %q", old.String())
+	}
+
+	// Exported loggers still allow overriding single levels.
+	var debug bytes.Buffer
+	l.DebugLogger.SetOutput(&debug)
+	l.Debug("d")
+	l.Info("i")
+	if got := debug.String(); got != "[DEBUG] d\n" {
+		t.Errorf("debug output %q", got)
+	}
+	if got := lines(&buf); got[len(got)-1] != "[INFO] i" {
+		t.Errorf("last line %q, want info message", got[len(got)-1])
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	var errs, debug bytes.Buffer
+	if err := l.SetOutputFor(LOG_ERROR, &errs); err != nil {
+		t.Fatal(err)
+	}
+	l.DebugLogger.SetOutput(&debug) // changes made through exported loggers are reported too
+
+	outputs := l.Named("db").Outputs()
+	for lvl, w := range outputs {
+		want := io.Writer(buf)
+		switch lvl {
+		case LOG_ERROR:
+			want = &errs
+		case LOG_DEBUG:
+			want = &debug
+		}
+		if w != want {
+			t.Errorf("level %d: output %p, want %p", lvl, w, want)
+		}
+	}
+
+	// Messages land where Outputs says they do.
+	logs := []func(v ...interface{}){l.Emerg, l.Alert, l.Crit, l.Error, l.Warn, l.Notice, l.Info, l.Debug}
+	for _, fn := range logs {
+		fn("m")
+	}
+	want := map[io.Writer][]string{
+		buf:    {"[EMERG] m", "[ALERT] m", "[CRIT] m", "[WARN] m", "[NOTICE] m", "[INFO] m"},
+		&errs:  {"[ERROR] m"},
+		&debug: {"[DEBUG] m"},
+	}
+	for _, w := range []*bytes.Buffer{buf, &errs, &debug} {
+		if got := lines(w); strings.Join(got, "\n") != strings.Join(want[w], "\n") {
+			t.Errorf("output %p:\n%s\nwant:\n%s", w, strings.Join(got, "\n"), strings.Join(want[w], "\n"))
+		}
+	}
+}
+
+func TestSetContentRouter(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG)
+	var audit, errs, alerts bytes.Buffer
+	l.SetOutputFunc(func(level int) io.Writer {
+		if level <= LOG_ERROR {
+			return &errs
+		}
+		return buf
+	})
+	l.SetAlertSink(LOG_CRIT, &alerts)
+	l.SetContentRouter(func(level int, msg string) io.Writer {
+		if strings.HasPrefix(msg, "[AUDIT]") {
+			return &audit
+		}
+		return nil
+	})
+
+	l.Info("[AUDIT] login")
+	l.Info("hello")
+	l.Error("failed")
+	l.Crit("[AUDIT] breach") // the router takes precedence over output function
+	l.SetContentRouter(nil)
+	l.Info("[AUDIT] logout")
+
+	if got, want := audit.String(), "[INFO] [AUDIT] login\n[CRIT] [AUDIT] breach\n"; got != want {
+		t.Errorf("audit %q, want %q", got, want)
+	}
+	if got, want := buf.String(), "[INFO] hello\n[INFO] [AUDIT] logout\n"; got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+	if got, want := errs.String(), "[ERROR] failed\n"; got != want {
+		t.Errorf("errors %q, want %q", got, want)
+	}
+	// The alert sink receives routed messages too.
+	if got, want := alerts.String(), "[CRIT] [AUDIT] breach\n"; got != want {
+		t.Errorf("alerts %q, want %q", got, want)
+	}
+}
+
+func TestSetFlags(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_DEBUG, WithPrefixes(map[int]string{LOG_INFO: "I "}))
+	l.With("k", "v").SetFlags(log.LUTC | log.Ltime)
+	for lvl, lg := range l.loggers() {
+		if got := lg.Flags(); got != log.LUTC|log.Ltime {
+			t.Errorf("level %d: flags %#x, want %#x", lvl, got, log.LUTC|log.Ltime)
+		}
+	}
+	if got := l.InfoLogger.Prefix(); got != "I " {
+		t.Errorf("SetFlags changed prefix to %q", got)
+	}
+
+	l.Info("m")
+	l.Debug("m")
+	want := []string{"I 14:30:15 m", "[DEBUG] 14:30:15 m"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output %q, want %q", got, want)
+	}
+}
+
+func TestVerbosity(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_WARN)
+	db := l.Named("db")
+	check := func(got, want int) {
+		t.Helper()
+		if got != want || l.LogLevel() != want {
+			t.Errorf("returned %d, level %d; want %d", got, l.LogLevel(), want)
+		}
+	}
+
+	l.Notice("hidden")
+	check(l.IncreaseVerbosity(), LOG_NOTICE)
+	l.Notice("notice")
+	l.Info("hidden")
+	check(l.IncreaseVerbosity(), LOG_INFO)
+	check(l.IncreaseVerbosity(), LOG_DEBUG)
+	check(l.IncreaseVerbosity(), LOG_DEBUG) // clamped
+	l.Debug("debug")
+
+	// Named loggers change the level of their component only.
+	if got := db.DecreaseVerbosity(); got != LOG_INFO || db.LogLevel() != LOG_INFO || l.LogLevel() != LOG_DEBUG {
+		t.Errorf("named: returned %d, level %d, base level %d", got, db.LogLevel(), l.LogLevel())
+	}
+	db.Debug("hidden")
+	db.Info("db info")
+	l.Debug("debug again")
+
+	for i := 0; i < LOG_DEBUG; i++ {
+		l.DecreaseVerbosity()
+	}
+	check(l.DecreaseVerbosity(), LOG_EMERG) // clamped
+	l.Alert("hidden")
+	l.Emerg("emerg")
+
+	want := []string{"[NOTICE] notice", "[DEBUG] debug", "[INFO] db info logger=db", "[DEBUG] debug again",
+		"[EMERG] emerg"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSetLogLevelConcurrent(t *testing.T) {
+	const writers, messages = 4, 200
+	l, buf := newTestLogger(t, LOG_INFO)
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			l.SetLogLevel([]int{LOG_INFO, LOG_DEBUG}[i%2])
+			if lvl := l.LogLevel(); lvl != LOG_INFO && lvl != LOG_DEBUG {
+				t.Errorf("LogLevel() = %d while switching between info and debug", lvl)
+				return
+			}
+		}
+	}()
+
+	var logged sync.WaitGroup
+	for g := 0; g < writers; g++ {
+		logged.Add(1)
+		go func() {
+			defer logged.Done()
+			for i := 0; i < messages; i++ {
+				l.Infoln("info")
+				l.Debugln("debug")
+			}
+		}()
+	}
+	logged.Wait()
+	close(done)
+	wg.Wait()
+
+	infos := 0
+	for _, line := range lines(buf) {
+		switch line {
+		case "[INFO] info":
+			infos++
+		case "[DEBUG] debug":
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+	if infos != writers*messages {
+		t.Errorf("%d info lines, want %d", infos, writers*messages)
+	}
+}
+
+func TestDisabledAllocs(t *testing.T) {
+	l, _ := newTestLogger(t, LOG_NOTICE)
+	named := l.Named("db")
+	n := 1000
+	tests := []struct {
+		name   string
+		log    func()
+		allocs float64
+	}{
+		{"Infof", func() { l.Infof("user %s id %d", "ann", 7) }, 0},
+		{"Info", func() { l.Info("user", "ann") }, 0},
+		{"Debugln", func() { l.Debugln("user", "ann") }, 0},
+		{"Infow", func() { l.Infow("login", "user", "ann") }, 0},
+		{"named Infof", func() { named.Infof("user %s id %d", "ann", 7) }, 0},
+		// Variable n is converted to interface{} before Infof is even called.
+		{"Infof variable", func() { n++; l.Infof("id %d", n) }, 1},
+		{"Infof variable guarded", func() {
+			n++
+			if l.IsLevelEnabled(LOG_INFO) {
+				l.Infof("id %d", n)
+			}
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.log); allocs != tt.allocs {
+				t.Errorf("disabled call made %v allocations, want %v", allocs, tt.allocs)
+			}
+		})
+	}
+}
+
+func BenchmarkInfof(b *testing.B) {
+	for _, lvl := range []int{LOG_INFO, LOG_NOTICE} {
+		name := "enabled"
+		if lvl < LOG_INFO {
+			name = "disabled"
+		}
+		b.Run(name, func(b *testing.B) {
+			l, _ := New(lvl, ioutil.Discard)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				l.Infof("user %s id %d", "ann", 7)
+			}
+		})
+	}
+}
+
+func BenchmarkLogLevel(b *testing.B) {
+	l, _ := New(LOG_INFO, ioutil.Discard)
+	named := l.Named("db")
+	b.Run("root", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			l.LogLevel()
+		}
+	})
+	b.Run("named", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			named.LogLevel()
+		}
+	})
+	b.Run("EffectiveEnabled", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			l.EffectiveEnabled(LOG_DEBUG)
+		}
+	})
+}
+
+func BenchmarkFormat(b *testing.B) {
+	e := &Entry{Time: testTime, Level: LOG_INFO, Message: "request served",
+		Fields: []Field{String("method", "GET"), Int("status", 200), Float64("seconds", 0.25)}}
+	for _, tt := range []struct {
+		name   string
+		format Format
+	}{
+		{"text", FormatText},
+		{"JSON", FormatJSON},
+		{"logfmt", FormatLogfmt},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			f := tt.format.Formatter()
+			var buf []byte
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = f.Format(buf[:0], e)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package twigsnake
+
+import "fmt"
+
+// IsLevelEnabled reports whether messages of given level are printed. It is the same as EffectiveEnabled. Disabled
+// calls of printing methods return without formatting anything, but their arguments are converted to interface{} by
+// the caller before that, which allocates for non-constant values other than small integers, e.g. for n in
+// l.Debugf("%d", n) with n above 255. Guarding such calls with IsLevelEnabled avoids the allocation on hot paths.
+func (l *Logger) IsLevelEnabled(level int) bool {
+	return l.EffectiveEnabled(level)
+}
+
+// The Unchecked methods below print messages without consulting logging level at all. They are meant for hot paths
+// which have already checked IsLevelEnabled (or otherwise know the level is enabled) and want to avoid repeating the
+// check on every call; the caller is responsible for that check. Arguments are handled in the same manner as log.Print.
+
+// EmergUnchecked prints emergency messages regardless of logging level.
+func (l *Logger) EmergUnchecked(v ...interface{}) {
+	l.output(LOG_EMERG, fmt.Sprint(v...), nil)
+}
+
+// AlertUnchecked prints alert messages regardless of logging level.
+func (l *Logger) AlertUnchecked(v ...interface{}) {
+	l.output(LOG_ALERT, fmt.Sprint(v...), nil)
+}
+
+// CritUnchecked prints critical messages regardless of logging level.
+func (l *Logger) CritUnchecked(v ...interface{}) {
+	l.output(LOG_CRIT, fmt.Sprint(v...), nil)
+}
+
+// ErrorUnchecked prints error messages regardless of logging level.
+func (l *Logger) ErrorUnchecked(v ...interface{}) {
+	l.output(LOG_ERROR, fmt.Sprint(v...), nil)
+}
+
+// WarnUnchecked prints warning messages regardless of logging level.
+func (l *Logger) WarnUnchecked(v ...interface{}) {
+	l.output(LOG_WARN, fmt.Sprint(v...), nil)
+}
+
+// NoticeUnchecked prints notification messages regardless of logging level.
+func (l *Logger) NoticeUnchecked(v ...interface{}) {
+	l.output(LOG_NOTICE, fmt.Sprint(v...), nil)
+}
+
+// InfoUnchecked prints informational messages regardless of logging level.
+func (l *Logger) InfoUnchecked(v ...interface{}) {
+	l.output(LOG_INFO, fmt.Sprint(v...), nil)
+}
+
+// DebugUnchecked prints debugging messages regardless of logging level.
+func (l *Logger) DebugUnchecked(v ...interface{}) {
+	l.output(LOG_DEBUG, fmt.Sprint(v...), nil)
+}
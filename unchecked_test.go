@@ -0,0 +1,41 @@
+package twigsnake
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestUnchecked(t *testing.T) {
+	l, buf := newTestLogger(t, LOG_EMERG)
+	l.EmergUnchecked("emerg")
+	l.AlertUnchecked("alert")
+	l.CritUnchecked("crit")
+	l.ErrorUnchecked("error")
+	l.WarnUnchecked("warn")
+	l.NoticeUnchecked("notice")
+	l.InfoUnchecked("info", 1)
+	l.DebugUnchecked("debug")
+
+	want := []string{"[EMERG] emerg", "[ALERT] alert", "[CRIT] crit", "[ERROR] error", "[WARN] warn", "[NOTICE] notice",
+		"[INFO] info1", "[DEBUG] debug"}
+	if got := lines(buf); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("output:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func BenchmarkUnchecked(b *testing.B) {
+	l, _ := New(LOG_INFO, ioutil.Discard)
+	b.Run("Info", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info("message")
+		}
+	})
+	b.Run("InfoUnchecked", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.InfoUnchecked("message")
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package twigsnake
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// WatchLevelFile sets logging level from the file at path and then keeps polling the file every poll interval, applying
+// the level again whenever file contents change. File must contain a single level name understood by ParseLevel. This is
+// handy for configuration files mounted into containers (e.g. Kubernetes ConfigMaps), which are updated in place.
+//
+// Error is returned if the file can't be read or parsed initially. Later failures are reported through the logger itself
+// on error level and the current logging level is kept. Call returned stop function to terminate watching; it returns once
+// the watcher has exited and is safe to call more than once.
+func (l *Logger) WatchLevelFile(path string, poll time.Duration) (stop func(), err error) {
+	if poll <= 0 {
+		return nil, errors.New("poll interval must be positive")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lvl, err := ParseLevel(string(data))
+	if err != nil {
+		return nil, err
+	}
+	l.SetLogLevel(lvl)
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.watchLevelFile(path, poll, data, done)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		wg.Wait()
+	}, nil
+}
+
+// watchLevelFile polls level file until done is closed. Prev holds the last seen file contents; read errors are reported
+// only once until the file becomes readable again to avoid flooding the log.
+func (l *Logger) watchLevelFile(path string, poll time.Duration, prev []byte, done <-chan struct{}) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			if !failing {
+				l.Errorf("twigsnake: failed to read level file %s, keeping level %s: %v", path, levelNames[l.LogLevel()], err)
+				failing = true
+			}
+			continue
+		}
+		failing = false
+
+		if bytes.Equal(data, prev) {
+			continue
+		}
+		prev = data
+
+		lvl, err := ParseLevel(string(data))
+		if err != nil {
+			l.Errorf("twigsnake: bad level file %s, keeping level %s: %v", path, levelNames[l.LogLevel()], err)
+			continue
+		}
+		l.SetLogLevel(lvl)
+	}
+}
@@ -0,0 +1,113 @@
+package twigsnake
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// watchPoll is the poll interval of level files in tests.
+const watchPoll = time.Millisecond
+
+// waitFor polls cond until it holds, failing the test with given description if it doesn't within 5 seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(watchPoll) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+// output returns contents of buf written by l, reading it under the logger lock so that writes of the watcher don't
+// race with the test.
+func output(l *Logger, buf *bytes.Buffer) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return buf.String()
+}
+
+// writeLevelFile replaces contents of level file at path with s atomically, so that the watcher never sees the file
+// truncated or half written.
+func writeLevelFile(t *testing.T, path, s string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path+".tmp", []byte(s), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchLevelFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "level")
+	writeLevelFile(t, path, "warn\n")
+	l, buf := newTestLogger(t, LOG_INFO)
+
+	stop, err := l.WatchLevelFile(path, watchPoll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if got := l.LogLevel(); got != LOG_WARN {
+		t.Fatalf("initial level %d, want %d", got, LOG_WARN)
+	}
+
+	writeLevelFile(t, path, "debug")
+	waitFor(t, "reload", func() bool { return l.LogLevel() == LOG_DEBUG })
+
+	writeLevelFile(t, path, "verbose")
+	waitFor(t, "parse error", func() bool { return strings.Contains(output(l, buf), "bad level file") })
+	if got := l.LogLevel(); got != LOG_DEBUG {
+		t.Errorf("level %d after parse error, want %d", got, LOG_DEBUG)
+	}
+	want := "[ERROR] twigsnake: bad level file " + path + `, keeping level debug: unknown severity level "verbose"`
+	if got := strings.TrimSuffix(output(l, buf), "\n"); got != want {
+		t.Errorf("output %q, want %q", got, want)
+	}
+
+	writeLevelFile(t, path, "notice")
+	waitFor(t, "recovery", func() bool { return l.LogLevel() == LOG_NOTICE })
+
+	stop()
+	stop() // must be safe to call again
+	writeLevelFile(t, path, "error")
+	time.Sleep(20 * watchPoll)
+	if got := l.LogLevel(); got != LOG_NOTICE {
+		t.Errorf("level %d after stop, want %d", got, LOG_NOTICE)
+	}
+}
+
+func TestWatchLevelFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad")
+	writeLevelFile(t, bad, "verbose")
+	good := filepath.Join(dir, "good")
+	writeLevelFile(t, good, "debug")
+
+	tests := []struct {
+		name string
+		path string
+		poll time.Duration
+	}{
+		{"missing file", filepath.Join(dir, "missing"), watchPoll},
+		{"bad level", bad, watchPoll},
+		{"zero poll", good, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestLogger(t, LOG_INFO)
+			if stop, err := l.WatchLevelFile(tt.path, tt.poll); err == nil {
+				stop()
+				t.Fatal("no error")
+			}
+			if got := l.LogLevel(); got != LOG_INFO {
+				t.Errorf("level %d, want %d", got, LOG_INFO)
+			}
+		})
+	}
+}
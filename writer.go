@@ -0,0 +1,67 @@
+package twigsnake
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// levelWriter is io.Writer logging everything written to it at a fixed level, see Logger.Writer.
+type levelWriter struct {
+	l     *Logger
+	level int
+}
+
+// Writer returns io.Writer logging every write as a single message of given severity level, for APIs which accept
+// io.Writer as their log sink. A single trailing line break is stripped, so lines written by log.Logger aren't double
+// spaced. Writes below current logging level are discarded, and so are all writes when level is invalid and level
+// policy (see SetInvalidLevelPolicy) doesn't map it to a valid one. Write never fails.
+func (l *Logger) Writer(level int) io.Writer {
+	level, ok, _ := applyLevelPolicy(level)
+	if !ok {
+		return ioutil.Discard
+	}
+	return levelWriter{l: l, level: level}
+}
+
+// StdLogger returns standard log.Logger writing into given severity level through Writer, for APIs which require
+// concrete *log.Logger, e.g. http.Server:
+//
+//	srv := &http.Server{Addr: ":8080", ErrorLog: logger.StdLogger(twigsnake.LOG_ERROR)}
+//
+// The returned logger has empty prefix and no flags, so that prefix and timestamp are added by l only once. Call site,
+// if reported, is the caller of the log.Logger method rather than the standard log package itself.
+func (l *Logger) StdLogger(level int) *log.Logger {
+	return log.New(l.Writer(level), "", 0)
+}
+
+func (w levelWriter) Write(p []byte) (int, error) {
+	if w.l.EffectiveEnabled(w.level) {
+		w.l.outputAt(w.level, string(p), nil, stdLogCaller(1), time.Time{})
+	}
+	return len(p), nil
+}
+
+// stdLogCaller returns program counter (see addCaller) of the first function outside of the standard log package
+// found among callers of the function skip frames above caller of stdLogCaller, so that writes made by log.Logger are
+// attributed to its caller. Zero is returned if there is no such function.
+func stdLogCaller(skip int) uintptr {
+	var pcs [16]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	if n == 0 {
+		return 0
+	}
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, "log.") {
+			return f.PC + 1
+		}
+		if !more {
+			return 0
+		}
+	}
+}
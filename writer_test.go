@@ -0,0 +1,126 @@
+package twigsnake
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		write string
+		want  string
+	}{
+		{"single line", LOG_WARN, "disk full", "[WARN] disk full"},
+		{"trailing line break stripped", LOG_ERROR, "oops\n", "[ERROR] oops"},
+		{"disabled level", LOG_DEBUG, "hidden", ""},
+		{"invalid level", 42, "lost", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			n, err := l.Writer(tt.level).Write([]byte(tt.write))
+			if n != len(tt.write) || err != nil {
+				t.Errorf("Write = %d, %v; want %d, nil", n, err, len(tt.write))
+			}
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriterThroughStdLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int // logging level at the time of writing
+		log   func(std *log.Logger)
+		want  string
+	}{
+		{"Print", LOG_INFO, func(std *log.Logger) { std.Print("failed") }, "[ERROR] lib: failed"},
+		{"Println", LOG_INFO, func(std *log.Logger) { std.Println("failed", 1) }, "[ERROR] lib: failed 1"},
+		{"Printf with line break", LOG_INFO, func(std *log.Logger) { std.Printf("failed\n") }, "[ERROR] lib: failed"},
+		{"only one line break stripped", LOG_INFO, func(std *log.Logger) { std.Print("failed\n\n") },
+			"[ERROR] lib: failed\n"},
+		{"level threshold", LOG_CRIT, func(std *log.Logger) { std.Print("failed") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_INFO)
+			std := log.New(l.Writer(LOG_ERROR), "lib: ", 0)
+			l.SetLogLevel(tt.level)
+			tt.log(std)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("output %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdLoggerCaller(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(std *log.Logger)
+	}{
+		{"Print", func(std *log.Logger) { std.Print("m") }},
+		{"Printf", func(std *log.Logger) { std.Printf("%s", "m") }},
+		{"Println", func(std *log.Logger) { std.Println("m") }},
+		{"Output", func(std *log.Logger) { _ = std.Output(1, "m") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, buf := newTestLogger(t, LOG_DEBUG, WithFlags(log.Lshortfile|log.Lmsgprefix))
+			l.SetCallerFunc(true)
+			tt.log(l.Named("std").StdLogger(LOG_ERROR))
+			got := strings.TrimSuffix(buf.String(), "\n")
+			if !strings.HasPrefix(got, "writer_test.go:") ||
+				!strings.Contains(got, " [ERROR] m logger=std caller=twigsnake.TestStdLoggerCaller.func") {
+				t.Errorf("output %q, want call site and caller function in writer_test.go", got)
+			}
+		})
+	}
+}
+
+func TestStdLoggerHTTPServerErrorLog(t *testing.T) {
+	logged := make(chan string, 10)
+	w := funcWriter(func(p []byte) (int, error) {
+		logged <- string(p)
+		return len(p), nil
+	})
+	l, err := New(LOG_DEBUG, w, WithFlags(log.Lmsgprefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetCallerPackage(true)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Config.ErrorLog = l.StdLogger(LOG_ERROR)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// Plain HTTP request sent to HTTPS server fails the handshake, which the server reports through ErrorLog.
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET / HTTP/1.0\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-logged:
+		got = strings.TrimSuffix(got, "\n")
+		if !strings.HasPrefix(got, "[ERROR] http: TLS handshake error") || !strings.HasSuffix(got, " pkg=http") {
+			t.Errorf("output %q, want TLS handshake error attributed to package http", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("http.Server logged nothing")
+	}
+}